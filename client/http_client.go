@@ -11,10 +11,24 @@ import (
 	"crypto/x509"
 
 	"github.com/cloudfoundry-incubator/credhub-cli/config"
+	"github.com/cloudfoundry-incubator/credhub-cli/credhub"
 )
 
 const TIMEOUT_SECS = 45
 
+var timeout = time.Second * TIMEOUT_SECS
+
+// SetTimeout changes the timeout of requests made by the CLI. A timeout of zero
+// means no timeout.
+func SetTimeout(d time.Duration) {
+	timeout = d
+}
+
+// Timeout returns the timeout of requests made by the CLI.
+func Timeout() time.Duration {
+	return timeout
+}
+
 //go:generate counterfeiter . HttpClient
 
 type HttpClient interface {
@@ -22,16 +36,38 @@ type HttpClient interface {
 }
 
 func NewHttpClient(cfg config.Config) *http.Client {
+	var client *http.Client
+
 	parsedUrl, _ := url.Parse(cfg.ApiURL)
 	if parsedUrl.Scheme == "https" {
-		return newHttpsClient(cfg)
+		client = newHttpsClient(cfg)
 	} else {
-		return newHttpClient()
+		client = newHttpClient()
 	}
+
+	if cassette, mode, ok := Cassette(); ok {
+		transport, err := credhub.NewRecordingTransport(cassette, mode, client.Transport)
+		if err != nil {
+			log.Fatal(err)
+		}
+		client.Transport = transport
+	}
+
+	if w := TraceWriter(); w != nil {
+		client.Transport = credhub.NewTracingTransport(w, client.Transport)
+	}
+
+	return client
 }
 
 func newHttpClient() *http.Client {
-	return &http.Client{Timeout: time.Second * TIMEOUT_SECS}
+	client := &http.Client{Timeout: timeout}
+
+	if dial := ProxyDialer(); dial != nil {
+		client.Transport = &http.Transport{Dial: dial}
+	}
+
+	return client
 }
 
 func newHttpsClient(cfg config.Config) *http.Client {
@@ -54,12 +90,18 @@ func newHttpsClient(cfg config.Config) *http.Client {
 	}
 
 	tr := &http.Transport{
+		Proxy:           http.ProxyFromEnvironment,
 		TLSClientConfig: tlsConfig,
 	}
 
+	if dial := ProxyDialer(); dial != nil {
+		tr.Proxy = nil
+		tr.Dial = dial
+	}
+
 	client := &http.Client{
 		Transport: tr,
-		Timeout:   time.Second * TIMEOUT_SECS,
+		Timeout:   timeout,
 	}
 	return client
 }
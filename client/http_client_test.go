@@ -4,6 +4,7 @@ import (
 	"net/http"
 
 	"io/ioutil"
+	"time"
 
 	"github.com/cloudfoundry-incubator/credhub-cli/client"
 	"github.com/cloudfoundry-incubator/credhub-cli/config"
@@ -89,4 +90,24 @@ var _ = Describe("#NewHttpClient", func() {
 		Expect(httpsClient.Transport.(*http.Transport).TLSClientConfig.RootCAs).To(BeNil())
 	})
 
+	It("uses the proxy from the environment for https clients", func() {
+		cfg = config.Config{
+			ApiURL: "https://foo.bar",
+		}
+
+		httpsClient := client.NewHttpClient(cfg)
+		Expect(httpsClient.Transport.(*http.Transport).Proxy).NotTo(BeNil())
+	})
+
+	It("uses the configured timeout", func() {
+		defer client.SetTimeout(client.TIMEOUT_SECS * time.Second)
+
+		Expect(client.NewHttpClient(config.Config{ApiURL: "https://foo.bar"}).Timeout).To(Equal(45 * time.Second))
+
+		client.SetTimeout(90 * time.Second)
+
+		Expect(client.Timeout()).To(Equal(90 * time.Second))
+		Expect(client.NewHttpClient(config.Config{ApiURL: "http://foo.bar"}).Timeout).To(Equal(90 * time.Second))
+		Expect(client.NewHttpClient(config.Config{ApiURL: "https://foo.bar"}).Timeout).To(Equal(90 * time.Second))
+	})
 })
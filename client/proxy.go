@@ -0,0 +1,147 @@
+package client
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/cloudfoundry-incubator/credhub-cli/config"
+	"github.com/cloudfoundry-incubator/credhub-cli/errors"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+	"golang.org/x/net/proxy"
+)
+
+// DialFunc connects to the given address, like net.Dial.
+type DialFunc func(network, addr string) (net.Conn, error)
+
+var (
+	proxyOnce sync.Once
+	proxyDial DialFunc
+	proxyErr  error
+)
+
+// ProxyDialer returns the dial function for connections to the CredHub and UAA
+// servers set by CREDHUB_PROXY, or nil when CREDHUB_PROXY is not set. The proxy
+// is either a SOCKS5 server, given as socks5://[user:password@]host:port, or an
+// SSH tunnel through a jumpbox, given as
+// ssh+socks5://user@host[:port]?private-key=path[&known-hosts=path].
+//
+// The proxy is set up on the first connection, so an invalid CREDHUB_PROXY is
+// reported as a connection error.
+func ProxyDialer() DialFunc {
+	if os.Getenv("CREDHUB_PROXY") == "" {
+		return nil
+	}
+
+	return func(network, addr string) (net.Conn, error) {
+		proxyOnce.Do(func() {
+			proxyDial, proxyErr = NewProxyDialer(os.Getenv("CREDHUB_PROXY"))
+		})
+		if proxyErr != nil {
+			return nil, proxyErr
+		}
+
+		return proxyDial(network, addr)
+	}
+}
+
+// NewProxyDialer returns a dial function that connects through the proxy at
+// proxyURL. SSH tunnels are opened immediately and shared by every connection.
+func NewProxyDialer(proxyURL string) (DialFunc, error) {
+	parsed, err := url.Parse(proxyURL)
+	if err != nil {
+		return nil, errors.NewInvalidProxyError("", "the URL cannot be parsed")
+	}
+
+	switch parsed.Scheme {
+	case "socks5":
+		return socks5Dialer(parsed)
+	case "ssh+socks5":
+		return sshDialer(parsed)
+	default:
+		return nil, errors.NewInvalidProxyError(parsed.Scheme+"://"+parsed.Host, "the scheme must be socks5 or ssh+socks5")
+	}
+}
+
+func socks5Dialer(proxyURL *url.URL) (DialFunc, error) {
+	redacted := proxyURL.Scheme + "://" + proxyURL.Host
+
+	if proxyURL.Host == "" {
+		return nil, errors.NewInvalidProxyError(redacted, "a host is required")
+	}
+
+	var auth *proxy.Auth
+	if proxyURL.User != nil {
+		password, _ := proxyURL.User.Password()
+		auth = &proxy.Auth{User: proxyURL.User.Username(), Password: password}
+	}
+
+	dialer, err := proxy.SOCKS5("tcp", proxyURL.Host, auth, proxy.Direct)
+	if err != nil {
+		return nil, errors.NewInvalidProxyError(redacted, err.Error())
+	}
+
+	return dialer.Dial, nil
+}
+
+func sshDialer(proxyURL *url.URL) (DialFunc, error) {
+	redacted := proxyURL.Scheme + "://" + proxyURL.Host
+
+	if proxyURL.Hostname() == "" {
+		return nil, errors.NewInvalidProxyError(redacted, "a host is required")
+	}
+	if proxyURL.User == nil || proxyURL.User.Username() == "" {
+		return nil, errors.NewInvalidProxyError(redacted, "a user is required")
+	}
+
+	keyPath := proxyURL.Query().Get("private-key")
+	if keyPath == "" {
+		return nil, errors.NewInvalidProxyError(redacted, "the private-key parameter is required")
+	}
+
+	key, err := ioutil.ReadFile(keyPath)
+	if err != nil {
+		return nil, errors.NewInvalidProxyError(redacted, err.Error())
+	}
+
+	signer, err := ssh.ParsePrivateKey(key)
+	if err != nil {
+		return nil, errors.NewInvalidProxyError(redacted, fmt.Sprintf("the private key cannot be parsed: %s", err))
+	}
+
+	hostKeyCallback, err := jumpboxHostKeyCallback(proxyURL.Query().Get("known-hosts"))
+	if err != nil {
+		return nil, errors.NewInvalidProxyError(redacted, err.Error())
+	}
+
+	address := proxyURL.Host
+	if proxyURL.Port() == "" {
+		address = net.JoinHostPort(proxyURL.Hostname(), "22")
+	}
+
+	sshClient, err := ssh.Dial("tcp", address, &ssh.ClientConfig{
+		User:            proxyURL.User.Username(),
+		Auth:            []ssh.AuthMethod{ssh.PublicKeys(signer)},
+		HostKeyCallback: hostKeyCallback,
+	})
+	if err != nil {
+		return nil, errors.NewProxyConnectionError(redacted, err.Error())
+	}
+
+	return sshClient.Dial, nil
+}
+
+// jumpboxHostKeyCallback verifies the jumpbox against knownHostsPath, or
+// ~/.ssh/known_hosts when no path is given.
+func jumpboxHostKeyCallback(knownHostsPath string) (ssh.HostKeyCallback, error) {
+	if knownHostsPath == "" {
+		knownHostsPath = filepath.Join(config.UserHomeDir(), ".ssh", "known_hosts")
+	}
+
+	return knownhosts.New(knownHostsPath)
+}
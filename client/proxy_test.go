@@ -0,0 +1,231 @@
+package client_test
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/binary"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+
+	"github.com/cloudfoundry-incubator/credhub-cli/client"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+var _ = Describe("#NewProxyDialer", func() {
+	It("rejects unsupported schemes", func() {
+		_, err := client.NewProxyDialer("http://proxy.example.com:8080")
+
+		Expect(err).To(MatchError("The proxy 'http://proxy.example.com:8080' set by CREDHUB_PROXY is not valid: the scheme must be socks5 or ssh+socks5. Please update and retry your request."))
+	})
+
+	Describe("socks5", func() {
+		It("connects through the SOCKS5 server", func() {
+			listener, err := net.Listen("tcp", "127.0.0.1:0")
+			Expect(err).NotTo(HaveOccurred())
+			defer listener.Close()
+
+			greeting := make(chan []byte, 1)
+			go func() {
+				defer GinkgoRecover()
+				conn, err := listener.Accept()
+				Expect(err).NotTo(HaveOccurred())
+				defer conn.Close()
+
+				buf := make([]byte, 3)
+				io.ReadFull(conn, buf)
+				greeting <- buf
+			}()
+
+			dial, err := client.NewProxyDialer("socks5://" + listener.Addr().String())
+			Expect(err).NotTo(HaveOccurred())
+
+			dial("tcp", "credhub.example.com:8844")
+
+			Eventually(greeting).Should(Receive(Equal([]byte{5, 1, 0})))
+		})
+
+		It("does not show the password in errors", func() {
+			_, err := client.NewProxyDialer("socks5://user:secret@")
+
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).NotTo(ContainSubstring("secret"))
+		})
+	})
+
+	Describe("ssh+socks5", func() {
+		var (
+			dir            string
+			privateKeyPath string
+			knownHostsPath string
+			jumpbox        *sshJumpbox
+		)
+
+		BeforeEach(func() {
+			var err error
+			dir, err = ioutil.TempDir("", "credhub-proxy")
+			Expect(err).NotTo(HaveOccurred())
+
+			userKey, err := rsa.GenerateKey(rand.Reader, 2048)
+			Expect(err).NotTo(HaveOccurred())
+			privateKeyPath = filepath.Join(dir, "id_rsa")
+			Expect(ioutil.WriteFile(privateKeyPath, pem.EncodeToMemory(&pem.Block{
+				Type:  "RSA PRIVATE KEY",
+				Bytes: x509.MarshalPKCS1PrivateKey(userKey),
+			}), 0600)).To(Succeed())
+			userPublicKey, err := ssh.NewPublicKey(&userKey.PublicKey)
+			Expect(err).NotTo(HaveOccurred())
+
+			jumpbox = startSSHJumpbox(userPublicKey)
+
+			knownHostsPath = filepath.Join(dir, "known_hosts")
+			Expect(ioutil.WriteFile(knownHostsPath, []byte(knownhosts.Line([]string{jumpbox.address}, jumpbox.hostKey)+"\n"), 0600)).To(Succeed())
+		})
+
+		AfterEach(func() {
+			jumpbox.listener.Close()
+			os.RemoveAll(dir)
+		})
+
+		It("connects through the jumpbox", func() {
+			target, err := net.Listen("tcp", "127.0.0.1:0")
+			Expect(err).NotTo(HaveOccurred())
+			defer target.Close()
+			go func() {
+				conn, err := target.Accept()
+				if err == nil {
+					conn.Write([]byte("hello from credhub"))
+					conn.Close()
+				}
+			}()
+
+			dial, err := client.NewProxyDialer("ssh+socks5://jumpbox-user@" + jumpbox.address + "?private-key=" + privateKeyPath + "&known-hosts=" + knownHostsPath)
+			Expect(err).NotTo(HaveOccurred())
+
+			conn, err := dial("tcp", target.Addr().String())
+			Expect(err).NotTo(HaveOccurred())
+			defer conn.Close()
+
+			greeting, err := ioutil.ReadAll(conn)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(greeting)).To(Equal("hello from credhub"))
+			Expect(<-jumpbox.users).To(Equal("jumpbox-user"))
+		})
+
+		It("refuses a jumpbox whose host key is not known", func() {
+			Expect(ioutil.WriteFile(knownHostsPath, nil, 0600)).To(Succeed())
+
+			_, err := client.NewProxyDialer("ssh+socks5://jumpbox-user@" + jumpbox.address + "?private-key=" + privateKeyPath + "&known-hosts=" + knownHostsPath)
+
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("A connection could not be opened through the proxy 'ssh+socks5://" + jumpbox.address + "'"))
+		})
+
+		It("requires a private key", func() {
+			_, err := client.NewProxyDialer("ssh+socks5://jumpbox-user@" + jumpbox.address)
+
+			Expect(err).To(MatchError("The proxy 'ssh+socks5://" + jumpbox.address + "' set by CREDHUB_PROXY is not valid: the private-key parameter is required. Please update and retry your request."))
+		})
+
+		It("requires a user", func() {
+			_, err := client.NewProxyDialer("ssh+socks5://" + jumpbox.address + "?private-key=" + privateKeyPath)
+
+			Expect(err).To(MatchError("The proxy 'ssh+socks5://" + jumpbox.address + "' set by CREDHUB_PROXY is not valid: a user is required. Please update and retry your request."))
+		})
+	})
+})
+
+type sshJumpbox struct {
+	listener net.Listener
+	address  string
+	hostKey  ssh.PublicKey
+	users    chan string
+}
+
+// startSSHJumpbox runs an SSH server that accepts userKey and forwards
+// direct-tcpip channels, as a jumpbox does.
+func startSSHJumpbox(userKey ssh.PublicKey) *sshJumpbox {
+	hostKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	Expect(err).NotTo(HaveOccurred())
+	hostSigner, err := ssh.NewSignerFromKey(hostKey)
+	Expect(err).NotTo(HaveOccurred())
+
+	jumpbox := &sshJumpbox{hostKey: hostSigner.PublicKey(), users: make(chan string, 1)}
+
+	serverConfig := &ssh.ServerConfig{
+		PublicKeyCallback: func(conn ssh.ConnMetadata, key ssh.PublicKey) (*ssh.Permissions, error) {
+			if string(key.Marshal()) != string(userKey.Marshal()) {
+				return nil, io.EOF
+			}
+			jumpbox.users <- conn.User()
+			return nil, nil
+		},
+	}
+	serverConfig.AddHostKey(hostSigner)
+
+	jumpbox.listener, err = net.Listen("tcp", "127.0.0.1:0")
+	Expect(err).NotTo(HaveOccurred())
+	jumpbox.address = jumpbox.listener.Addr().String()
+
+	go func() {
+		for {
+			conn, err := jumpbox.listener.Accept()
+			if err != nil {
+				return
+			}
+			go serveSSH(conn, serverConfig)
+		}
+	}()
+
+	return jumpbox
+}
+
+func serveSSH(conn net.Conn, serverConfig *ssh.ServerConfig) {
+	_, channels, requests, err := ssh.NewServerConn(conn, serverConfig)
+	if err != nil {
+		return
+	}
+	go ssh.DiscardRequests(requests)
+
+	for newChannel := range channels {
+		if newChannel.ChannelType() != "direct-tcpip" {
+			newChannel.Reject(ssh.UnknownChannelType, "unsupported channel type")
+			continue
+		}
+
+		extra := newChannel.ExtraData()
+		hostLength := binary.BigEndian.Uint32(extra)
+		host := string(extra[4 : 4+hostLength])
+		port := binary.BigEndian.Uint32(extra[4+hostLength:])
+
+		target, err := net.Dial("tcp", net.JoinHostPort(host, fmt.Sprint(port)))
+		if err != nil {
+			newChannel.Reject(ssh.ConnectionFailed, err.Error())
+			continue
+		}
+
+		channel, channelRequests, err := newChannel.Accept()
+		if err != nil {
+			target.Close()
+			continue
+		}
+		go ssh.DiscardRequests(channelRequests)
+
+		go func() {
+			io.Copy(channel, target)
+			channel.Close()
+		}()
+		go func() {
+			io.Copy(target, channel)
+			target.Close()
+		}()
+	}
+}
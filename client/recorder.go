@@ -0,0 +1,22 @@
+package client
+
+import (
+	"os"
+
+	"github.com/cloudfoundry-incubator/credhub-cli/credhub"
+)
+
+// Cassette returns the cassette that requests to the CredHub and UAA servers are
+// recorded to, as set by CREDHUB_RECORD, or replayed from, as set by
+// CREDHUB_REPLAY. ok is false when neither is set.
+func Cassette() (cassette string, mode credhub.RecorderMode, ok bool) {
+	if cassette := os.Getenv("CREDHUB_REPLAY"); cassette != "" {
+		return cassette, credhub.ReplayMode, true
+	}
+
+	if cassette := os.Getenv("CREDHUB_RECORD"); cassette != "" {
+		return cassette, credhub.RecordMode, true
+	}
+
+	return "", credhub.RecordMode, false
+}
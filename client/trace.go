@@ -0,0 +1,46 @@
+package client
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"sync"
+)
+
+var (
+	traceOnce   sync.Once
+	traceWriter io.Writer
+)
+
+// TraceWriter returns where requests to the CredHub and UAA servers are traced,
+// as set by CREDHUB_TRACE: standard error when it is true, or the named file.
+// It returns nil when tracing is off.
+func TraceWriter() io.Writer {
+	traceOnce.Do(func() {
+		traceWriter = openTrace(os.Getenv("CREDHUB_TRACE"))
+	})
+
+	return traceWriter
+}
+
+func openTrace(trace string) io.Writer {
+	if trace == "" {
+		return nil
+	}
+
+	if enabled, err := strconv.ParseBool(trace); err == nil {
+		if enabled {
+			return os.Stderr
+		}
+		return nil
+	}
+
+	file, err := os.OpenFile(trace, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Requests are not being traced: %s\n", err)
+		return nil
+	}
+
+	return file
+}
@@ -0,0 +1,271 @@
+package commands
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"runtime"
+	"syscall"
+	"text/template"
+	"time"
+
+	"github.com/cloudfoundry-incubator/credhub-cli/config"
+	"github.com/cloudfoundry-incubator/credhub-cli/credhub"
+	"github.com/cloudfoundry-incubator/credhub-cli/credhub/auth"
+	"github.com/cloudfoundry-incubator/credhub-cli/credhub/credentials"
+	"github.com/cloudfoundry-incubator/credhub-cli/models"
+)
+
+type AgentCommand struct {
+	ConfigFile string `short:"c" long:"config" required:"yes" description:"File describing the templates to render"`
+	Once       bool   `long:"once" description:"Render templates once and exit instead of polling for changes"`
+}
+
+type agent struct {
+	cfg         config.Config
+	client      *credhub.CredHub
+	templates   []*agentTemplate
+	credentials map[string]credentials.Credential
+}
+
+type agentTemplate struct {
+	models.AgentTemplate
+	template *template.Template
+
+	// names of the credentials referenced during the last render
+	names map[string]bool
+}
+
+func (cmd AgentCommand) Execute([]string) error {
+	var agentConfig models.AgentConfig
+	if err := agentConfig.ReadFile(cmd.ConfigFile); err != nil {
+		return err
+	}
+
+	cfg, err := config.ReadConfig()
+	if err != nil {
+		return err
+	}
+
+	credhubClient, err := initializeCredhubClient(cfg)
+	if err != nil {
+		return err
+	}
+
+	a := &agent{
+		cfg:         cfg,
+		client:      credhubClient,
+		credentials: map[string]credentials.Credential{},
+	}
+
+	for _, t := range agentConfig.Templates {
+		if err := a.addTemplate(t); err != nil {
+			return err
+		}
+	}
+
+	for _, t := range a.templates {
+		if err := a.render(t); err != nil {
+			return err
+		}
+	}
+
+	if cmd.Once {
+		return nil
+	}
+
+	return a.run(agentConfig.Interval, agentConfig.TokenRefreshInterval)
+}
+
+func (a *agent) addTemplate(t models.AgentTemplate) error {
+	contents := t.Contents
+	if t.Source != "" {
+		data, err := ioutil.ReadFile(t.Source)
+		if err != nil {
+			return err
+		}
+		contents = string(data)
+	}
+
+	at := &agentTemplate{AgentTemplate: t}
+
+	parsed, err := template.New(t.Destination).Option("missingkey=error").Funcs(template.FuncMap{
+		"credential": func(name string) (credentials.Credential, error) {
+			at.names[name] = true
+			return a.credential(name)
+		},
+	}).Parse(contents)
+	if err != nil {
+		return err
+	}
+
+	at.template = parsed
+	a.templates = append(a.templates, at)
+
+	return nil
+}
+
+func (a *agent) run(interval, tokenRefreshInterval time.Duration) error {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(signals)
+
+	poll := time.NewTicker(interval)
+	defer poll.Stop()
+
+	tokenRefresh := time.NewTicker(tokenRefreshInterval)
+	defer tokenRefresh.Stop()
+
+	for {
+		select {
+		case <-signals:
+			return nil
+		case <-tokenRefresh.C:
+			if err := a.refreshToken(); err != nil {
+				fmt.Fprintln(os.Stderr, "Failed to refresh token: "+err.Error())
+			}
+		case <-poll.C:
+			changed := a.poll()
+			for _, t := range a.templates {
+				if !t.references(changed) {
+					continue
+				}
+				if err := a.render(t); err != nil {
+					fmt.Fprintln(os.Stderr, err.Error())
+				}
+			}
+		}
+	}
+}
+
+// credential returns the cached version of the named credential, fetching it
+// the first time a template references it.
+func (a *agent) credential(name string) (credentials.Credential, error) {
+	if credential, ok := a.credentials[name]; ok {
+		return credential, nil
+	}
+
+	credential, err := a.client.GetLatestVersion(name)
+	if err != nil {
+		return credentials.Credential{}, err
+	}
+
+	a.credentials[name] = credential
+	return credential, nil
+}
+
+// poll fetches the latest version of every cached credential and returns the
+// names of those whose version has changed. Credentials that cannot be fetched
+// keep their last known version.
+func (a *agent) poll() map[string]bool {
+	changed := map[string]bool{}
+
+	for name, current := range a.credentials {
+		latest, err := a.client.GetLatestVersion(name)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to fetch %s: %s\n", name, err.Error())
+			continue
+		}
+
+		if latest.Id != current.Id || latest.VersionCreatedAt != current.VersionCreatedAt {
+			a.credentials[name] = latest
+			changed[name] = true
+		}
+	}
+
+	return changed
+}
+
+// render executes the template and, when the output differs from the file
+// on disk, writes it and runs the template's command.
+func (a *agent) render(t *agentTemplate) error {
+	t.names = map[string]bool{}
+
+	var buf bytes.Buffer
+	if err := t.template.Execute(&buf, nil); err != nil {
+		return err
+	}
+
+	existing, err := ioutil.ReadFile(t.Destination)
+	if err == nil && bytes.Equal(existing, buf.Bytes()) {
+		return nil
+	}
+
+	if err := writeFileAtomically(t.Destination, buf.Bytes(), t.Perms); err != nil {
+		return err
+	}
+
+	fmt.Println("Rendered " + t.Destination)
+
+	if t.Command == "" {
+		return nil
+	}
+
+	return runShellCommand(t.Command)
+}
+
+func (a *agent) refreshToken() error {
+	oauth, ok := a.client.Auth.(*auth.OAuthStrategy)
+	if !ok {
+		return nil
+	}
+
+	if err := oauth.Refresh(); err != nil {
+		return err
+	}
+
+	return saveTokens(a.cfg, a.client)
+}
+
+func (t *agentTemplate) references(names map[string]bool) bool {
+	for name := range names {
+		if t.names[name] {
+			return true
+		}
+	}
+	return false
+}
+
+// writeFileAtomically replaces path with data so that readers never observe
+// a partially written file or one with looser permissions than perm.
+func writeFileAtomically(path string, data []byte, perm os.FileMode) error {
+	tmp, err := ioutil.TempFile(filepath.Dir(path), "."+filepath.Base(path))
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if err := tmp.Chmod(perm); err != nil {
+		tmp.Close()
+		return err
+	}
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
+
+func runShellCommand(command string) error {
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.Command("cmd", "/C", command)
+	} else {
+		cmd = exec.Command("sh", "-c", command)
+	}
+
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	return cmd.Run()
+}
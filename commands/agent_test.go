@@ -0,0 +1,126 @@
+package commands_test
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sync/atomic"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gbytes"
+	. "github.com/onsi/gomega/gexec"
+	. "github.com/onsi/gomega/ghttp"
+)
+
+var _ = Describe("Agent", func() {
+	var (
+		dir         string
+		agentConfig string
+		destination string
+		reloaded    string
+	)
+
+	BeforeEach(func() {
+		login()
+
+		var err error
+		dir, err = ioutil.TempDir("", "credhub-agent")
+		Expect(err).NotTo(HaveOccurred())
+
+		destination = filepath.Join(dir, "db.yml")
+		reloaded = filepath.Join(dir, "reloaded")
+		agentConfig = filepath.Join(dir, "agent.yml")
+
+		Expect(ioutil.WriteFile(agentConfig, []byte(fmt.Sprintf(`
+interval: 100ms
+templates:
+- contents: "password: {{ (credential \"/db/password\").Value }}\n"
+  destination: %s
+  command: echo reload >> %s
+`, destination, reloaded)), 0600)).To(Succeed())
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(dir)
+	})
+
+	ItRequiresAuthentication("agent", "-c", "../test/test_agent_config.yml", "--once")
+
+	It("renders templates with strict permissions and runs the command", func() {
+		responseJson := fmt.Sprintf(STRING_CREDENTIAL_ARRAY_RESPONSE_JSON, "password", "/db/password", "s3cr3t")
+
+		server.RouteToHandler("GET", "/api/v1/data",
+			CombineHandlers(
+				VerifyRequest("GET", "/api/v1/data", "name=%2Fdb%2Fpassword&versions=1"),
+				RespondWith(http.StatusOK, responseJson),
+			),
+		)
+
+		session := runCommand("agent", "-c", agentConfig, "--once")
+
+		Eventually(session).Should(Exit(0))
+		Expect(session.Out).To(Say("Rendered " + destination))
+
+		contents, err := ioutil.ReadFile(destination)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(contents)).To(Equal("password: s3cr3t\n"))
+
+		info, err := os.Stat(destination)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(info.Mode().Perm()).To(Equal(os.FileMode(0600)))
+
+		Expect(ioutil.ReadFile(reloaded)).To(Equal([]byte("reload\n")))
+	})
+
+	It("does not run the command when the rendered file is unchanged", func() {
+		responseJson := fmt.Sprintf(STRING_CREDENTIAL_ARRAY_RESPONSE_JSON, "password", "/db/password", "s3cr3t")
+		server.RouteToHandler("GET", "/api/v1/data", RespondWith(http.StatusOK, responseJson))
+		Expect(ioutil.WriteFile(destination, []byte("password: s3cr3t\n"), 0600)).To(Succeed())
+
+		session := runCommand("agent", "-c", agentConfig, "--once")
+
+		Eventually(session).Should(Exit(0))
+		Expect(reloaded).NotTo(BeAnExistingFile())
+	})
+
+	It("re-renders and reloads when the credential version changes", func() {
+		var requests int32
+		server.RouteToHandler("GET", "/api/v1/data", func(w http.ResponseWriter, r *http.Request) {
+			id, value := "1", "old-password"
+			if atomic.AddInt32(&requests, 1) > 2 {
+				id, value = "2", "new-password"
+			}
+			w.Write([]byte(`{"data":[{"type":"password","id":"` + id + `","name":"/db/password","version_created_at":"` + TIMESTAMP + `","value":"` + value + `"}]}`))
+		})
+
+		session, err := Start(exec.Command(commandPath, "agent", "-c", agentConfig), GinkgoWriter, GinkgoWriter)
+		Expect(err).NotTo(HaveOccurred())
+		defer session.Kill()
+
+		Eventually(func() (string, error) {
+			contents, err := ioutil.ReadFile(destination)
+			return string(contents), err
+		}, "5s").Should(Equal("password: new-password\n"))
+
+		Eventually(func() (string, error) {
+			contents, err := ioutil.ReadFile(reloaded)
+			return string(contents), err
+		}).Should(Equal("reload\nreload\n"))
+	})
+
+	It("errors when the template references a missing credential", func() {
+		server.RouteToHandler("GET", "/api/v1/data",
+			RespondWith(http.StatusNotFound, `{"error":"The request could not be completed because the credential does not exist or you do not have sufficient authorization."}`),
+		)
+
+		session := runCommand("agent", "-c", agentConfig, "--once")
+
+		Eventually(session).Should(Exit(1))
+		Expect(session.Err).To(Say("the credential does not exist"))
+		Expect(destination).NotTo(BeAnExistingFile())
+	})
+})
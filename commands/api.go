@@ -28,7 +28,10 @@ type ApiPositionalArgs struct {
 }
 
 func (cmd ApiCommand) Execute([]string) error {
-	cfg := config.ReadConfig()
+	cfg, err := config.ReadOrNewConfig()
+	if err != nil {
+		return err
+	}
 	serverUrl := targetUrl(cmd)
 
 	if serverUrl == "" {
@@ -38,52 +41,77 @@ func (cmd ApiCommand) Execute([]string) error {
 			return errors.NewNoApiUrlSetError()
 		}
 	} else {
-		caCerts, err := ReadOrGetCaCerts(cmd.CaCerts)
+		err := SetTarget(&cfg, serverUrl, cmd.CaCerts, cmd.SkipTlsValidation)
 		if err != nil {
 			return err
 		}
 
-		serverUrl = util.AddDefaultSchemeIfNecessary(serverUrl)
+		err = config.UpdateConfig(func(latest *config.Config) error {
+			if latest.AuthURL != cfg.AuthURL {
+				MarkTokensAsRevokedInConfig(latest)
+			}
+			copyTarget(latest, cfg)
+			return nil
+		})
 
-		credhubInfo, err := GetApiInfo(serverUrl, caCerts, cmd.SkipTlsValidation)
 		if err != nil {
-			return errors.NewNetworkError(err)
+			return err
 		}
+	}
 
-		if credhubInfo.AuthServer.URL != cfg.AuthURL {
-			RevokeTokenIfNecessary(cfg)
-			MarkTokensAsRevokedInConfig(&cfg)
-		}
+	return nil
+}
 
-		cfg.ApiURL = serverUrl
-		cfg.AuthURL = credhubInfo.AuthServer.URL
-		cfg.ServerVersion = credhubInfo.App.Version
-		cfg.InsecureSkipVerify = cmd.SkipTlsValidation
-		cfg.CaCerts = caCerts
+func SetTarget(cfg *config.Config, serverUrl string, caCertFlags []string, skipTlsValidation bool) error {
+	caCerts, err := ReadOrGetCaCerts(caCertFlags)
+	if err != nil {
+		return err
+	}
 
-		err = verifyAuthServerConnection(cfg, cmd.SkipTlsValidation)
-		if err != nil {
-			return errors.NewNetworkError(err)
-		}
+	serverUrl = util.AddDefaultSchemeIfNecessary(serverUrl)
 
-		err = PrintWarnings(serverUrl, cmd.SkipTlsValidation)
-		if err != nil {
-			return err
-		}
-		fmt.Println("Setting the target url:", cfg.ApiURL)
+	credhubInfo, err := GetApiInfo(serverUrl, caCerts, skipTlsValidation)
+	if err != nil {
+		return errors.NewNetworkError(err)
+	}
+
+	if credhubInfo.AuthServer.URL != cfg.AuthURL {
+		RevokeTokenIfNecessary(*cfg)
+		MarkTokensAsRevokedInConfig(cfg)
+	}
 
-		err = config.WriteConfig(cfg)
+	cfg.ApiURL = serverUrl
+	cfg.AuthURL = credhubInfo.AuthServer.URL
+	cfg.ServerVersion = credhubInfo.App.Version
+	cfg.InsecureSkipVerify = skipTlsValidation
+	cfg.CaCerts = caCerts
 
-		if err != nil {
-			return err
-		}
+	err = verifyAuthServerConnection(*cfg, skipTlsValidation)
+	if err != nil {
+		return errors.NewNetworkError(err)
 	}
 
+	err = PrintWarnings(serverUrl, skipTlsValidation)
+	if err != nil {
+		return err
+	}
+	fmt.Println("Setting the target url:", cfg.ApiURL)
+
 	return nil
 }
 
+// copyTarget sets the server, auth server and trusted CAs of dst to those of
+// src, leaving its tokens alone.
+func copyTarget(dst *config.Config, src config.Config) {
+	dst.ApiURL = src.ApiURL
+	dst.AuthURL = src.AuthURL
+	dst.ServerVersion = src.ServerVersion
+	dst.InsecureSkipVerify = src.InsecureSkipVerify
+	dst.CaCerts = src.CaCerts
+}
+
 func GetApiInfo(serverUrl string, caCerts []string, skipTlsValidation bool) (*server.Info, error) {
-	credhubClient, err := credhub.New(serverUrl, credhub.CaCerts(caCerts...), credhub.SkipTLSValidation(skipTlsValidation))
+	credhubClient, err := credhub.New(serverUrl, credhub.CaCerts(caCerts...), credhub.SkipTLSValidation(skipTlsValidation), transportOptions())
 	if err != nil {
 		return nil, err
 	}
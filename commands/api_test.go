@@ -40,6 +40,20 @@ var _ = Describe("API", func() {
 			Eventually(session.Out).Should(Say(server.URL()))
 		})
 
+		Context("with a config file that cannot be parsed", func() {
+			BeforeEach(func() {
+				err := ioutil.WriteFile(config.ConfigPath(), []byte("{not-json"), 0600)
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("reports the corrupt config", func() {
+				session := runCommand("api")
+
+				Eventually(session).Should(Exit(10))
+				Expect(session.Err).To(Say("The config file at .* could not be parsed"))
+			})
+		})
+
 		Context("with no set API", func() {
 			BeforeEach(func() {
 				config.WriteConfig(config.Config{})
@@ -48,7 +62,7 @@ var _ = Describe("API", func() {
 			It("errors with a helpful message", func() {
 				session := runCommand("api")
 
-				Eventually(session).Should(Exit(1))
+				Eventually(session).Should(Exit(3))
 				Expect(session.Err).To(Say("An API target is not set. Please target the location of your server with `credhub api --server api.example.com` to continue."))
 			})
 		})
@@ -69,13 +83,13 @@ var _ = Describe("API", func() {
 
 			newAuthServer.RouteToHandler("GET", "/info", RespondWith(http.StatusOK, ""))
 
-			cfg := config.ReadConfig()
+			cfg, _ := config.ReadConfig()
 			cfg.AuthURL = authServer.URL()
 			cfg.AccessToken = VALID_ACCESS_TOKEN
 			config.WriteConfig(cfg)
 
 			session := runCommand("api", apiServer.URL())
-			newCfg := config.ReadConfig()
+			newCfg, _ := config.ReadConfig()
 
 			Eventually(session).Should(Exit(0))
 			Expect(authServer.ReceivedRequests()).Should(HaveLen(1))
@@ -92,7 +106,7 @@ var _ = Describe("API", func() {
 						}`),
 			)
 
-			cfg := config.ReadConfig()
+			cfg, _ := config.ReadConfig()
 			cfg.AccessToken = "fake_token"
 			cfg.RefreshToken = "fake_refresh"
 			config.WriteConfig(cfg)
@@ -100,7 +114,7 @@ var _ = Describe("API", func() {
 			session := runCommand("api", apiServer.URL(), "--skip-tls-validation")
 
 			Eventually(session).Should(Exit(0))
-			newCfg := config.ReadConfig()
+			newCfg, _ := config.ReadConfig()
 			Expect(newCfg.AccessToken).To(Equal("fake_token"))
 			Expect(newCfg.RefreshToken).To(Equal("fake_refresh"))
 			Expect(authServer.ReceivedRequests()).Should(HaveLen(0))
@@ -110,7 +124,7 @@ var _ = Describe("API", func() {
 			apiServer := NewServer()
 			apiServer.RouteToHandler("GET", "/info", RespondWith(http.StatusNotFound, ""))
 
-			cfg := config.ReadConfig()
+			cfg, _ := config.ReadConfig()
 			cfg.AuthURL = authServer.URL()
 			cfg.AccessToken = "fake_token"
 			cfg.RefreshToken = "fake_refresh"
@@ -118,8 +132,8 @@ var _ = Describe("API", func() {
 
 			session := runCommand("api", apiServer.URL())
 
-			Eventually(session).Should(Exit(1))
-			newCfg := config.ReadConfig()
+			Eventually(session).Should(Exit(7))
+			newCfg, _ := config.ReadConfig()
 			Expect(newCfg.AccessToken).To(Equal("fake_token"))
 			Expect(newCfg.RefreshToken).To(Equal("fake_refresh"))
 			Expect(authServer.ReceivedRequests()).Should(HaveLen(0))
@@ -132,7 +146,7 @@ var _ = Describe("API", func() {
 
 			Eventually(session).Should(Exit(0))
 
-			cfg := config.ReadConfig()
+			cfg, _ := config.ReadConfig()
 			Expect(cfg.ApiURL).To(Equal(server.URL()))
 		})
 
@@ -177,7 +191,7 @@ var _ = Describe("API", func() {
 				Eventually(session).Should(Exit(0))
 				Eventually(session.Out).Should(Say(theServerUrl))
 
-				cfg := config.ReadConfig()
+				cfg, _ := config.ReadConfig()
 
 				Expect(cfg.AuthURL).To(Equal("https://example.com"))
 				Expect(len(cfg.CaCerts)).To(Equal(0))
@@ -245,7 +259,7 @@ var _ = Describe("API", func() {
 					session := runCommand("api", theServer.URL())
 					Eventually(session).Should(Exit(0))
 
-					cfg := config.ReadConfig()
+					cfg, _ := config.ReadConfig()
 					Expect(cfg.ApiURL).To(Equal(theServer.URL()))
 					Expect(cfg.AuthURL).To(Equal("https://example.com"))
 					Expect(cfg.InsecureSkipVerify).To(Equal(false))
@@ -264,7 +278,7 @@ var _ = Describe("API", func() {
 
 				Context("when the user skips TLS validation", func() {
 					BeforeEach(func() {
-						cfg := config.ReadConfig()
+						cfg, _ := config.ReadConfig()
 						cfg.CaCerts = []string{}
 						config.WriteConfig(cfg)
 					})
@@ -287,12 +301,12 @@ var _ = Describe("API", func() {
 						session := runCommand("api", "-s", theServerUrl, "--skip-tls-validation")
 
 						Eventually(session).Should(Exit(0))
-						cfg := config.ReadConfig()
+						cfg, _ := config.ReadConfig()
 						Expect(cfg.InsecureSkipVerify).To(Equal(true))
 					})
 
 					It("resets skip-tls flag in the config file", func() {
-						cfg := config.ReadConfig()
+						cfg, _ := config.ReadConfig()
 						cfg.InsecureSkipVerify = true
 						err := config.WriteConfig(cfg)
 						Expect(err).NotTo(HaveOccurred())
@@ -300,7 +314,7 @@ var _ = Describe("API", func() {
 						session := runCommand("api", "-s", theServerUrl)
 
 						Eventually(session).Should(Exit(0))
-						cfg = config.ReadConfig()
+						cfg, _ = config.ReadConfig()
 						Expect(cfg.InsecureSkipVerify).To(Equal(false))
 					})
 
@@ -310,7 +324,7 @@ var _ = Describe("API", func() {
 						theServerUrl = setUpServer(theServer)
 						session := runCommand("api", "-s", theServerUrl)
 
-						Eventually(session).Should(Exit(1))
+						Eventually(session).Should(Exit(7))
 						Eventually(session.Err).Should(Say("Error connecting to the targeted API"))
 					})
 
@@ -325,7 +339,7 @@ var _ = Describe("API", func() {
 
 					It("records skip-tls into config file even with http URLs (will do nothing with that value)", func() {
 						session := runCommand("api", theServer.URL(), "--skip-tls-validation")
-						cfg := config.ReadConfig()
+						cfg, _ := config.ReadConfig()
 
 						Eventually(session).Should(Exit(0))
 						Expect(cfg.InsecureSkipVerify).To(Equal(true))
@@ -342,7 +356,7 @@ var _ = Describe("API", func() {
 						session := runCommand("api", "-s", theServer.URL(), "--ca-cert", "../test/server-tls-ca.pem")
 						Eventually(session).Should(Exit(0))
 
-						cfg := config.ReadConfig()
+						cfg, _ := config.ReadConfig()
 						Expect(cfg.CaCerts).To(Equal([]string{string(testCa)}))
 					})
 				})
@@ -357,7 +371,7 @@ var _ = Describe("API", func() {
 						session := runCommand("api", "-s", theServer.URL(), "--ca-cert", "../test/server-tls-ca.pem", "--ca-cert", "../test/extra-ca.pem")
 						Eventually(session).Should(Exit(0))
 
-						cfg := config.ReadConfig()
+						cfg, _ := config.ReadConfig()
 						Expect(cfg.CaCerts).To(Equal([]string{string(ca1), string(ca2)}))
 					})
 				})
@@ -373,35 +387,35 @@ var _ = Describe("API", func() {
 					session := runCommand("api", "-s", server.URL(), "--ca-cert", "../test/server-tls-ca.pem", "--ca-cert", "../test/auth-tls-ca.pem", "--ca-cert", "../test/extra-ca.pem")
 					Eventually(session).Should(Exit(0))
 
-					cfg := config.ReadConfig()
+					cfg, _ := config.ReadConfig()
 					Expect(cfg.CaCerts).To(Equal([]string{string(serverCa), string(authCa), string(extraCa)}))
 
 					session = runCommand("api", "-s", server.URL(), "--ca-cert", "../test/server-tls-ca.pem", "--ca-cert", "../test/auth-tls-ca.pem")
 					Eventually(session).Should(Exit(0))
 
-					cfg = config.ReadConfig()
+					cfg, _ = config.ReadConfig()
 					Expect(cfg.CaCerts).To(Equal([]string{string(serverCa), string(authCa)}))
 				})
 
 				It("returns an error if no cert is valid for CredHub", func() {
-					previousCfg := config.ReadConfig()
+					previousCfg, _ := config.ReadConfig()
 					session := runCommand("api", "-s", server.URL(), "--ca-cert", "../test/auth-tls-ca.pem")
 
-					Eventually(session).Should(Exit(1))
+					Eventually(session).Should(Exit(7))
 					Eventually(session.Err).Should(Say("certificate signed by unknown authority"))
 
-					cfg := config.ReadConfig()
+					cfg, _ := config.ReadConfig()
 					Expect(cfg.CaCerts).To(Equal(previousCfg.CaCerts))
 				})
 
 				It("returns an error if no cert is valid for the auth server", func() {
-					previousCfg := config.ReadConfig()
+					previousCfg, _ := config.ReadConfig()
 					session := runCommand("api", "-s", server.URL(), "--ca-cert", "../test/server-tls-ca.pem")
 
-					Eventually(session).Should(Exit(1))
+					Eventually(session).Should(Exit(7))
 					Eventually(session.Err).Should(Say("certificate signed by unknown authority"))
 
-					cfg := config.ReadConfig()
+					cfg, _ := config.ReadConfig()
 					Expect(cfg.CaCerts).To(Equal(previousCfg.CaCerts))
 				})
 
@@ -417,7 +431,7 @@ var _ = Describe("API", func() {
 					session := runCommandWithEnv([]string{"CREDHUB_CA_CERT=../test/server-tls-ca.pem"}, "api", server.URL())
 					Eventually(session).Should(Exit(0))
 
-					cfg := config.ReadConfig()
+					cfg, _ := config.ReadConfig()
 					Expect(cfg.CaCerts).To(ConsistOf([]string{string(serverCa)}))
 				})
 			})
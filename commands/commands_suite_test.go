@@ -7,13 +7,17 @@ import (
 	. "github.com/onsi/gomega/gexec"
 	. "github.com/onsi/gomega/ghttp"
 
+	"encoding/json"
 	"fmt"
 	"io"
 	"io/ioutil"
 	"net/http"
+	"net/url"
 	"os"
 	"os/exec"
 	"runtime"
+	"strings"
+	"sync"
 	"testing"
 
 	"crypto/tls"
@@ -21,6 +25,7 @@ import (
 	"path/filepath"
 
 	"github.com/cloudfoundry-incubator/credhub-cli/config"
+	"github.com/cloudfoundry-incubator/credhub-cli/credhub"
 	test_util "github.com/cloudfoundry-incubator/credhub-cli/test"
 )
 
@@ -234,7 +239,7 @@ func ItRequiresAuthentication(args ...string) {
 
 		session := runCommand(args...)
 
-		Eventually(session).Should(Exit(1))
+		Eventually(session).Should(Exit(3))
 		Expect(session.Err).To(Say("You are not currently authenticated. Please log in to continue."))
 	})
 }
@@ -242,7 +247,7 @@ func ItRequiresAuthentication(args ...string) {
 func ItRequiresAnAPIToBeSet(args ...string) {
 	Describe("requires an API endpoint", func() {
 		BeforeEach(func() {
-			cfg := config.ReadConfig()
+			cfg, _ := config.ReadConfig()
 			cfg.ApiURL = ""
 			config.WriteConfig(cfg)
 		})
@@ -251,7 +256,7 @@ func ItRequiresAnAPIToBeSet(args ...string) {
 			It("requires an API endpoint", func() {
 				session := runCommand(args...)
 
-				Eventually(session).Should(Exit(1))
+				Eventually(session).Should(Exit(3))
 				Expect(session.Err).To(Say("An API target is not set. Please target the location of your server with `credhub api --server api.example.com` to continue."))
 			})
 		})
@@ -260,19 +265,23 @@ func ItRequiresAnAPIToBeSet(args ...string) {
 			It("requires an API endpoint", func() {
 				session := runCommandWithEnv([]string{"CREDHUB_CLIENT=test_client", "CREDHUB_SECRET=test_secret"}, args...)
 
-				Eventually(session).Should(Exit(1))
+				Eventually(session).Should(Exit(3))
 				Expect(session.Err).To(Say("An API target is not set. Please target the location of your server with `credhub api --server api.example.com` to continue."))
 			})
 		})
 	})
 }
 
-func ItAutomaticallyLogsIn(method string, responseFixtureFile string, endpoint string, args ...string) {
-	var serverResponse string
+func ItAutomaticallyLogsIn(cassette string, args ...string) {
+	var interaction credhub.Interaction
+	var method, endpoint, query string
 	Describe("automatic authentication", func() {
 		BeforeEach(func() {
-			buf, _ := ioutil.ReadFile(filepath.Join("testdata", responseFixtureFile))
-			serverResponse = string(buf)
+			interaction = recordedInteraction(cassette)
+			method = interaction.Request.Method
+			recordedURL, err := url.Parse(interaction.Request.URL)
+			Expect(err).NotTo(HaveOccurred())
+			endpoint, query = recordedURL.Path, recordedURL.RawQuery
 		})
 		AfterEach(func() {
 			server.Reset()
@@ -282,11 +291,11 @@ func ItAutomaticallyLogsIn(method string, responseFixtureFile string, endpoint s
 			BeforeEach(func() {
 				server.AppendHandlers(
 					CombineHandlers(
-						VerifyRequest(method, endpoint),
+						VerifyRequest(method, endpoint, query),
 						VerifyHeader(http.Header{
 							"Authorization": []string{"Bearer 2YotnFZFEjr1zCsicMWpAA"},
 						}),
-						RespondWith(http.StatusOK, serverResponse),
+						RespondWith(interaction.Response.Status, interaction.Response.Body, interaction.Response.Headers),
 					),
 				)
 			})
@@ -320,7 +329,7 @@ func ItAutomaticallyLogsIn(method string, responseFixtureFile string, endpoint s
 			BeforeEach(func() {
 				server.AppendHandlers(
 					CombineHandlers(
-						VerifyRequest(method, endpoint),
+						VerifyRequest(method, endpoint, query),
 						VerifyHeader(http.Header{
 							"Authorization": []string{"Bearer test-access-token"},
 						}),
@@ -343,11 +352,11 @@ func ItAutomaticallyLogsIn(method string, responseFixtureFile string, endpoint s
 
 				server.AppendHandlers(
 					CombineHandlers(
-						VerifyRequest(method, endpoint),
+						VerifyRequest(method, endpoint, query),
 						VerifyHeader(http.Header{
 							"Authorization": []string{"Bearer new-token"},
 						}),
-						RespondWith(http.StatusOK, serverResponse),
+						RespondWith(interaction.Response.Status, interaction.Response.Body, interaction.Response.Headers),
 					),
 				)
 			})
@@ -359,3 +368,85 @@ func ItAutomaticallyLogsIn(method string, responseFixtureFile string, endpoint s
 		})
 	})
 }
+
+// recordedInteraction returns the first interaction in the cassette
+// testdata/cassettes/<name>.json, recorded with CREDHUB_RECORD.
+func recordedInteraction(name string) credhub.Interaction {
+	contents, err := ioutil.ReadFile(filepath.Join("testdata", "cassettes", name+".json"))
+	Expect(err).NotTo(HaveOccurred())
+
+	var cassette credhub.Cassette
+	Expect(json.Unmarshal(contents, &cassette)).To(Succeed())
+	Expect(cassette.Interactions).NotTo(BeEmpty())
+
+	return cassette.Interactions[0]
+}
+
+// credentialStore serves find by path or partial name, get, set and delete requests for a fixed set
+// of credentials and records every change made through it.
+type credentialStore struct {
+	mu          sync.Mutex
+	credentials map[string]string
+	sets        []map[string]interface{}
+	deletes     []string
+}
+
+func newCredentialStore(srv *Server, credentials map[string]string) *credentialStore {
+	store := &credentialStore{credentials: credentials}
+
+	srv.RouteToHandler("GET", "/api/v1/data", func(w http.ResponseWriter, r *http.Request) {
+		store.mu.Lock()
+		defer store.mu.Unlock()
+
+		path, nameLike := r.URL.Query().Get("path"), r.URL.Query().Get("name-like")
+		if path != "" || nameLike != "" {
+			var found []string
+			for name := range store.credentials {
+				if path != "" && !strings.HasPrefix(name, strings.TrimSuffix("/"+strings.TrimPrefix(path, "/"), "/")+"/") {
+					continue
+				}
+				if nameLike != "" && !strings.Contains(name, nameLike) {
+					continue
+				}
+				found = append(found, `{"name":"`+name+`","version_created_at":"`+TIMESTAMP+`"}`)
+			}
+			w.Write([]byte(`{"credentials":[` + strings.Join(found, ",") + `]}`))
+			return
+		}
+
+		credential, ok := store.credentials[r.URL.Query().Get("name")]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"error":"The request could not be completed because the credential does not exist or you do not have sufficient authorization."}`))
+			return
+		}
+		w.Write([]byte(`{"data":[` + credential + `]}`))
+	})
+
+	srv.RouteToHandler("PUT", "/api/v1/data", func(w http.ResponseWriter, r *http.Request) {
+		store.mu.Lock()
+		defer store.mu.Unlock()
+
+		body, _ := ioutil.ReadAll(r.Body)
+		var request map[string]interface{}
+		Expect(json.Unmarshal(body, &request)).To(Succeed())
+		store.sets = append(store.sets, request)
+
+		value, _ := json.Marshal(request["value"])
+		w.Write([]byte(fmt.Sprintf(`{"type":"%s","id":"`+UUID+`","name":"%s","version_created_at":"`+TIMESTAMP+`","value":%s}`, request["type"], request["name"], value)))
+	})
+
+	srv.RouteToHandler("DELETE", "/api/v1/data", func(w http.ResponseWriter, r *http.Request) {
+		store.mu.Lock()
+		defer store.mu.Unlock()
+
+		store.deletes = append(store.deletes, r.URL.Query().Get("name"))
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	return store
+}
+
+func valueCredential(credType, name, value string) string {
+	return fmt.Sprintf(STRING_CREDENTIAL_RESPONSE_JSON, credType, name, value)
+}
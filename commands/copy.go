@@ -0,0 +1,244 @@
+package commands
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/cloudfoundry-incubator/credhub-cli/config"
+	"github.com/cloudfoundry-incubator/credhub-cli/credhub"
+	"github.com/cloudfoundry-incubator/credhub-cli/credhub/credentials"
+	"github.com/cloudfoundry-incubator/credhub-cli/errors"
+)
+
+type CopyCommand struct {
+	From        string `long:"from" required:"yes" description:"Name of the credential or path of the credentials to copy"`
+	To          string `long:"to" required:"yes" description:"Name or path to copy to"`
+	AllVersions bool   `long:"all-versions" description:"Copy every version, oldest first, instead of only the current one"`
+	Force       bool   `long:"force" description:"Add the copied versions to credentials that already exist at the destination"`
+}
+
+type MoveCommand struct {
+	From        string `long:"from" required:"yes" description:"Name of the credential or path of the credentials to move"`
+	To          string `long:"to" required:"yes" description:"Name or path to move to"`
+	AllVersions bool   `long:"all-versions" description:"Move every version, oldest first, instead of only the current one"`
+	Force       bool   `long:"force" description:"Add the moved versions to credentials that already exist at the destination"`
+}
+
+func (cmd CopyCommand) Execute([]string) error {
+	return transferCredentials(cmd.From, cmd.To, cmd.AllVersions, cmd.Force, false)
+}
+
+func (cmd MoveCommand) Execute([]string) error {
+	return transferCredentials(cmd.From, cmd.To, cmd.AllVersions, cmd.Force, true)
+}
+
+// transferCredentials copies the credential or subtree at from to to, and then
+// deletes the originals when move is set.
+func transferCredentials(from, to string, allVersions, force, move bool) error {
+	if absoluteName(from) == absoluteName(to) {
+		return errors.NewCopySameSourceAndDestinationError()
+	}
+
+	cfg, err := config.ReadConfig()
+	if err != nil {
+		return err
+	}
+
+	credhubClient, err := initializeCredhubClient(cfg)
+	if err != nil {
+		return err
+	}
+
+	renames, existing, err := transferRenames(credhubClient, from, to)
+	if err != nil {
+		return err
+	}
+
+	if !force {
+		for _, newName := range renames {
+			if existing[absoluteName(newName)] {
+				return errors.NewCopyDestinationExistsError(newName)
+			}
+		}
+	}
+
+	history := map[string][]credentials.Credential{}
+	latest := map[string]credentials.Credential{}
+	for oldName := range renames {
+		versions, err := transferVersions(credhubClient, oldName, allVersions)
+		if err != nil {
+			return err
+		}
+		history[oldName] = versions
+		latest[oldName] = versions[len(versions)-1]
+	}
+
+	var references []credentials.Credential
+	if move {
+		references, err = externalCaReferences(credhubClient, renames, latest)
+		if err != nil {
+			return err
+		}
+	}
+
+	order := caFirstOrder(latest)
+	for _, oldName := range order {
+		for _, version := range history[oldName] {
+			_, err := credhubClient.SetCredential(renames[oldName], version.Type, transferValue(version, renames), true)
+			if err != nil {
+				return err
+			}
+		}
+		fmt.Printf("  %s -> %s (%s)\n", oldName, renames[oldName], latest[oldName].Type)
+	}
+
+	if !move {
+		fmt.Printf("Copied %d credential(s).\n", len(order))
+		return nil
+	}
+
+	for _, credential := range references {
+		_, err := credhubClient.SetCredential(credential.Name, credential.Type, transferValue(credential, renames), true)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("  %s now references %s\n", credential.Name, renames[certificateCaName(credential)])
+	}
+
+	for _, oldName := range order {
+		if err := credhubClient.Delete(oldName); err != nil {
+			return err
+		}
+	}
+
+	fmt.Printf("Moved %d credential(s).\n", len(order))
+
+	return nil
+}
+
+// transferRenames maps the names of the credentials to transfer to their new
+// names, and returns the names that already exist at the destination. When
+// from is a path, every credential below it keeps its name relative to the
+// path; otherwise from is a single credential renamed to to.
+func transferRenames(credhubClient *credhub.CredHub, from, to string) (map[string]string, map[string]bool, error) {
+	renames := map[string]string{}
+	existing := map[string]bool{}
+
+	results, err := credhubClient.FindByPath(from)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if len(results.Credentials) > 0 {
+		fromPrefix := strings.TrimSuffix(absoluteName(from), "/") + "/"
+		toPrefix := strings.TrimSuffix(absoluteName(to), "/") + "/"
+		for _, result := range results.Credentials {
+			renames[result.Name] = toPrefix + strings.TrimPrefix(result.Name, fromPrefix)
+		}
+
+		destination, err := credhubClient.FindByPath(to)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, result := range destination.Credentials {
+			existing[absoluteName(result.Name)] = true
+		}
+
+		return renames, existing, nil
+	}
+
+	source, err := credhubClient.FindByPartialName(from)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, result := range source.Credentials {
+		if absoluteName(result.Name) == absoluteName(from) {
+			renames[result.Name] = absoluteName(to)
+		}
+	}
+	if len(renames) == 0 {
+		return nil, nil, errors.NewCopySourceNotFoundError(from)
+	}
+
+	destination, err := credhubClient.FindByPartialName(to)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, result := range destination.Credentials {
+		existing[absoluteName(result.Name)] = true
+	}
+
+	return renames, existing, nil
+}
+
+// transferVersions returns the versions of the named credential to write,
+// oldest first.
+func transferVersions(credhubClient *credhub.CredHub, name string, allVersions bool) ([]credentials.Credential, error) {
+	if !allVersions {
+		credential, err := credhubClient.GetLatestVersion(name)
+		return []credentials.Credential{credential}, err
+	}
+
+	versions, err := credhubClient.GetAllVersions(name)
+	if err != nil {
+		return nil, err
+	}
+	if len(versions) == 0 {
+		return nil, errors.NewCopySourceNotFoundError(name)
+	}
+
+	for i, j := 0, len(versions)-1; i < j; i, j = i+1, j-1 {
+		versions[i], versions[j] = versions[j], versions[i]
+	}
+
+	return versions, nil
+}
+
+// externalCaReferences returns the latest versions of the certificates that are
+// not moved but reference a moved CA by ca_name, so that they can be updated to
+// reference its new name before it is deleted.
+func externalCaReferences(credhubClient *credhub.CredHub, renames map[string]string, moved map[string]credentials.Credential) ([]credentials.Credential, error) {
+	movesCertificate := false
+	for _, credential := range moved {
+		if credential.Type == "certificate" {
+			movesCertificate = true
+		}
+	}
+	if !movesCertificate {
+		return nil, nil
+	}
+
+	all, err := latestVersionsByPath(credhubClient, "/")
+	if err != nil {
+		return nil, err
+	}
+
+	var references []credentials.Credential
+	for _, name := range sortedNames(all) {
+		if _, ok := renames[name]; ok {
+			continue
+		}
+		if _, ok := renames[certificateCaName(all[name])]; ok {
+			references = append(references, all[name])
+		}
+	}
+
+	return references, nil
+}
+
+// transferValue returns the value to set for a transferred credential. A
+// certificate whose CA is transferred along with it references the CA by its
+// new name.
+func transferValue(credential credentials.Credential, renames map[string]string) interface{} {
+	value := rollbackValue(credential)
+
+	if newName, ok := renames[certificateCaName(credential)]; ok {
+		value.(map[string]interface{})["ca_name"] = newName
+	}
+
+	return value
+}
+
+func absoluteName(name string) string {
+	return "/" + strings.TrimPrefix(name, "/")
+}
@@ -0,0 +1,158 @@
+package commands_test
+
+import (
+	"net/http"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gbytes"
+	. "github.com/onsi/gomega/gexec"
+)
+
+var _ = Describe("Copy and move", func() {
+	var store *credentialStore
+
+	BeforeEach(func() {
+		login()
+
+		store = newCredentialStore(server, map[string]string{
+			"/old/password":   valueCredential("password", "/old/password", "secret"),
+			"/old/job/ca":     `{"type":"certificate","id":"` + UUID + `","name":"/old/job/ca","version_created_at":"` + TIMESTAMP + `","value":{"ca":"ca-pem","certificate":"ca-pem","private_key":"ca-key"}}`,
+			"/old/job/cert":   `{"type":"certificate","id":"` + UUID + `","name":"/old/job/cert","version_created_at":"` + TIMESTAMP + `","value":{"ca_name":"/old/job/ca","ca":"ca-pem","certificate":"cert-pem","private_key":"cert-key"}}`,
+			"/old/other":      `{"type":"certificate","id":"` + UUID + `","name":"/old/other","version_created_at":"` + TIMESTAMP + `","value":{"ca_name":"/shared/ca","ca":"shared-ca-pem","certificate":"other-pem","private_key":"other-key"}}`,
+			"/elsewhere/cert": `{"type":"certificate","id":"` + UUID + `","name":"/elsewhere/cert","version_created_at":"` + TIMESTAMP + `","value":{"ca_name":"/old/job/ca","ca":"ca-pem","certificate":"elsewhere-pem","private_key":"elsewhere-key"}}`,
+			"/existing/user":  `{"type":"user","id":"` + UUID + `","name":"/existing/user","version_created_at":"` + TIMESTAMP + `","value":{"username":"admin","password":"pass","password_hash":"hash"}}`,
+		})
+	})
+
+	ItRequiresAuthentication("copy", "--from", "/old", "--to", "/new")
+
+	It("copies every credential under a path, writing CAs first", func() {
+		session := runCommand("copy", "--from", "/old", "--to", "/new")
+
+		Eventually(session).Should(Exit(0))
+		Expect(session.Out).To(Say(`  /old/job/ca -> /new/job/ca \(certificate\)`))
+		Expect(session.Out).To(Say(`  /old/job/cert -> /new/job/cert \(certificate\)`))
+		Expect(session.Out).To(Say(`Copied 4 credential\(s\).`))
+
+		Expect(store.sets).To(Equal([]map[string]interface{}{
+			{"name": "/new/job/ca", "type": "certificate", "value": map[string]interface{}{"ca": "ca-pem", "certificate": "ca-pem", "private_key": "ca-key"}, "overwrite": true},
+			{"name": "/new/job/cert", "type": "certificate", "value": map[string]interface{}{"ca_name": "/new/job/ca", "certificate": "cert-pem", "private_key": "cert-key"}, "overwrite": true},
+			{"name": "/new/other", "type": "certificate", "value": map[string]interface{}{"ca_name": "/shared/ca", "certificate": "other-pem", "private_key": "other-key"}, "overwrite": true},
+			{"name": "/new/password", "type": "password", "value": "secret", "overwrite": true},
+		}))
+		Expect(store.deletes).To(BeEmpty())
+	})
+
+	It("renames a single credential when moving it", func() {
+		session := runCommand("move", "--from", "/existing/user", "--to", "/renamed/user")
+
+		Eventually(session).Should(Exit(0))
+		Expect(session.Out).To(Say(`  /existing/user -> /renamed/user \(user\)`))
+		Expect(session.Out).To(Say(`Moved 1 credential\(s\).`))
+
+		Expect(store.sets).To(Equal([]map[string]interface{}{
+			{"name": "/renamed/user", "type": "user", "value": map[string]interface{}{"username": "admin", "password": "pass"}, "overwrite": true},
+		}))
+		Expect(store.deletes).To(Equal([]string{"/existing/user"}))
+	})
+
+	It("replays every version oldest first", func() {
+		server.RouteToHandler("GET", "/api/v1/data", func(w http.ResponseWriter, r *http.Request) {
+			query := r.URL.Query()
+			switch {
+			case query.Get("path") != "":
+				w.Write([]byte(`{"credentials":[]}`))
+			case query.Get("name-like") == "/old/password":
+				w.Write([]byte(`{"credentials":[{"name":"/old/password","version_created_at":"` + TIMESTAMP + `"}]}`))
+			case query.Get("name-like") != "":
+				w.Write([]byte(`{"credentials":[]}`))
+			default:
+				Expect(query.Get("versions")).To(BeEmpty())
+				w.Write([]byte(`{"data":[` +
+					valueCredential("password", "/old/password", "newest") + `,` +
+					valueCredential("password", "/old/password", "oldest") + `]}`))
+			}
+		})
+
+		session := runCommand("move", "--from", "/old/password", "--to", "/new/password", "--all-versions")
+
+		Eventually(session).Should(Exit(0))
+		Expect(store.sets).To(Equal([]map[string]interface{}{
+			{"name": "/new/password", "type": "password", "value": "oldest", "overwrite": true},
+			{"name": "/new/password", "type": "password", "value": "newest", "overwrite": true},
+		}))
+		Expect(store.deletes).To(Equal([]string{"/old/password"}))
+	})
+
+	It("errors when a credential has no versions to replay", func() {
+		server.RouteToHandler("GET", "/api/v1/data", func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Query().Get("name-like") == "/old/password" {
+				w.Write([]byte(`{"credentials":[{"name":"/old/password","version_created_at":"` + TIMESTAMP + `"}]}`))
+				return
+			}
+			w.Write([]byte(`{"credentials":[],"data":[]}`))
+		})
+
+		session := runCommand("move", "--from", "/old/password", "--to", "/new/password", "--all-versions")
+
+		Eventually(session).ShouldNot(Exit(0))
+		Expect(store.sets).To(BeEmpty())
+		Expect(store.deletes).To(BeEmpty())
+	})
+
+	It("moves a path given without a leading slash", func() {
+		session := runCommand("move", "--from", "old/job", "--to", "new/job")
+
+		Eventually(session).Should(Exit(0))
+		Expect(session.Out).To(Say(`  /old/job/ca -> /new/job/ca \(certificate\)`))
+		Expect(session.Out).To(Say(`  /old/job/cert -> /new/job/cert \(certificate\)`))
+
+		Expect(store.sets).To(ContainElement(HaveKeyWithValue("name", "/new/job/ca")))
+		Expect(store.sets).To(ContainElement(HaveKeyWithValue("name", "/new/job/cert")))
+		Expect(store.deletes).To(Equal([]string{"/old/job/ca", "/old/job/cert"}))
+	})
+
+	It("updates certificates outside the moved path that reference a moved CA", func() {
+		session := runCommand("move", "--from", "/old/job", "--to", "/new/job")
+
+		Eventually(session).Should(Exit(0))
+		Expect(session.Out).To(Say(`  /elsewhere/cert now references /new/job/ca`))
+		Expect(session.Out).To(Say(`Moved 2 credential\(s\).`))
+
+		Expect(store.sets).To(Equal([]map[string]interface{}{
+			{"name": "/new/job/ca", "type": "certificate", "value": map[string]interface{}{"ca": "ca-pem", "certificate": "ca-pem", "private_key": "ca-key"}, "overwrite": true},
+			{"name": "/new/job/cert", "type": "certificate", "value": map[string]interface{}{"ca_name": "/new/job/ca", "certificate": "cert-pem", "private_key": "cert-key"}, "overwrite": true},
+			{"name": "/elsewhere/cert", "type": "certificate", "value": map[string]interface{}{"ca_name": "/new/job/ca", "certificate": "elsewhere-pem", "private_key": "elsewhere-key"}, "overwrite": true},
+		}))
+		Expect(store.deletes).To(Equal([]string{"/old/job/ca", "/old/job/cert"}))
+	})
+
+	It("refuses to overwrite an existing credential unless forced", func() {
+		session := runCommand("copy", "--from", "/old/password", "--to", "/existing/user")
+
+		Eventually(session).Should(Exit(2))
+		Expect(session.Err).To(Say("The credential '/existing/user' already exists. Use --force to add the copied versions to it."))
+		Expect(store.sets).To(BeEmpty())
+
+		session = runCommand("copy", "--from", "/old/password", "--to", "/existing/user", "--force")
+
+		Eventually(session).Should(Exit(0))
+		Expect(store.sets).To(HaveLen(1))
+	})
+
+	It("errors when the source does not exist", func() {
+		session := runCommand("move", "--from", "/missing", "--to", "/new")
+
+		Eventually(session).Should(Exit(5))
+		Expect(session.Err).To(Say("No credential or path named '/missing' was found."))
+	})
+
+	It("refuses to move a credential onto itself", func() {
+		session := runCommand("move", "--from", "/old", "--to", "old")
+
+		Eventually(session).Should(Exit(2))
+		Expect(session.Err).To(Say("The source and destination must be different."))
+		Expect(store.deletes).To(BeEmpty())
+	})
+})
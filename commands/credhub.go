@@ -1,19 +1,37 @@
 package commands
 
+import "time"
+
 type CredhubCommand struct {
-	Api        ApiCommand        `command:"api"        alias:"a" description:"Get or set the CredHub API target where commands are sent" long-description:"Get or set the CredHub API target where commands are sent. The api command without any flags will return the current target. If --ca-cert or --skip-tls-validation are provided, these preferences will be cached for future requests."`
-	Delete     DeleteCommand     `command:"delete"     alias:"d" description:"Delete a credential" long-description:"Delete a credential. This will delete all versions of the credential.\n\n More information: https://credhub-api.cfapps.io/#delete-credentials"`
-	Find       FindCommand       `command:"find"       alias:"f" description:"Find stored credential names or paths based on query parameters" long-description:"Find stored credential names or paths based on query parameters.\n\n More information: https://credhub-api.cfapps.io/#find-credentials"`
-	Generate   GenerateCommand   `command:"generate"   alias:"n" description:"Generate and set a credential value" long-description:"Set a credential with generated value(s). A type must be specified when generating a credential. The provided flags are used to set parameters for the credential that is generated, e.g. a certificate credential may use --common-name, --duration and --self-sign to generate an appropriate value. Supported credential types are prefixed in the flag description.\n\n More information: https://credhub-api.cfapps.io/#generate-credentials"`
-	Get        GetCommand        `command:"get"        alias:"g" description:"Get a credential value" long-description:"Get a credential value by name or ID.\n\n More information: https://credhub-api.cfapps.io/#get-credentials"`
-	Import     ImportCommand     `command:"import"     alias:"i" description:"Set multiple credential values" long-description:"Set multiple credential values from import file. File must be in yaml format containing a list of credentials under the key 'credentials'. Name, type and value are required for each credential in the list.\n\n More information: https://credhub-api.cfapps.io/#bulk-import"`
-	Login      LoginCommand      `command:"login"      alias:"l" description:"Authenticate with CredHub" long-description:"Authenticate with CredHub. UAA password and client credential grants are supported. If client credentials exist in the environment, authentication will be performed automatically without the need to explicitly call this command."`
-	Logout     LogoutCommand     `command:"logout"     alias:"o" description:"Discard authenticated user session" long-description:"Discard authenticated session. Refresh token revocation will be attempted for password grants."`
-	Regenerate RegenerateCommand `command:"regenerate" alias:"r" description:"Generate and set a credential value using the same attributes as the stored value" long-description:"Set a credential with a generated value using the same attributes as the stored value.\n\n More information: https://credhub-api.cfapps.io/#regenerate-credentials"`
-	Set        SetCommand        `command:"set"        alias:"s" description:"Set a credential with a provided value" long-description:"Set a credential with provided value(s). A type must be specified when setting a credential. The provided flags are used to set specific values of a credential, e.g. a certificate credential may use --root, --certificate and --private to set each value. Supported credential types are prefixed in the flag description.\n\n More information: https://credhub-api.cfapps.io/#set-credentials"`
+	Agent            AgentCommand            `command:"agent"      description:"Render credentials into files and reload on change" long-description:"Render Go templates to files and run a reload command whenever a referenced credential changes. Templates reference credentials with {{ (credential \"/name\").Value }}. Credentials are polled every interval, comparing version ids, until the agent is interrupted."`
+	Api              ApiCommand              `command:"api"        alias:"a" description:"Get or set the CredHub API target where commands are sent" long-description:"Get or set the CredHub API target where commands are sent. The api command without any flags will return the current target. If --ca-cert or --skip-tls-validation are provided, these preferences will be cached for future requests."`
+	Copy             CopyCommand             `command:"copy"       description:"Copy a credential or all credentials under a path" long-description:"Copy a credential to a new name, or every credential under a path to the same relative names under a new path, preserving types and values. With --all-versions the full history is replayed oldest first. Certificates that reference a copied CA by ca_name are updated to reference the copy."`
+	Delete           DeleteCommand           `command:"delete"     alias:"d" description:"Delete a credential" long-description:"Delete a credential. This will delete all versions of the credential. Several credentials may be deleted at once by selecting them with --path, --name-like or --regex; the matched credentials are listed and must be confirmed unless --force is given.\n\n More information: https://credhub-api.cfapps.io/#delete-credentials"`
+	Diff             DiffCommand             `command:"diff"       description:"Show differences between credential versions, a path and an export file, or two profiles" long-description:"Show a unified diff of credential values. Compare two versions of a credential with --name, --from-id and --to-id; the credentials under --path with an export --file; or the credentials under --path on two profiles with --from-profile and --to-profile. Use --mask to hide secret values and only show which fields changed."`
+	DockerCredential DockerCredentialCommand `command:"docker-credential" description:"Docker credential helper backed by CredHub" long-description:"Implements the Docker credential helper protocol, storing registry credentials as user credentials under --path. Reads the server URL or credential JSON from stdin. When the CLI is installed as 'docker-credential-credhub', Docker can use it directly with '\"credsStore\": \"credhub\"'."`
+	Edit             EditCommand             `command:"edit"       description:"Edit a credential value in your editor" long-description:"Open the current value of a credential in $VISUAL or $EDITOR, as YAML or, with --json, JSON; string values are edited as plain text. The edited value is validated for the credential's type, the difference is shown and a new version is set. Nothing is set if the credential changed on the server while it was being edited."`
+	Find             FindCommand             `command:"find"       alias:"f" description:"Find stored credential names or paths based on query parameters" long-description:"Find stored credential names or paths based on query parameters.\n\n More information: https://credhub-api.cfapps.io/#find-credentials"`
+	Generate         GenerateCommand         `command:"generate"   alias:"n" description:"Generate and set a credential value" long-description:"Set a credential with generated value(s). A type must be specified when generating a credential. The provided flags are used to set parameters for the credential that is generated, e.g. a certificate credential may use --common-name, --duration and --self-sign to generate an appropriate value. Supported credential types are prefixed in the flag description.\n\n More information: https://credhub-api.cfapps.io/#generate-credentials"`
+	GitCredential    GitCredentialCommand    `command:"git-credential" description:"Git credential helper backed by CredHub" long-description:"Implements the git credential helper protocol, storing repository credentials as user credentials under --path named by protocol, host and, when provided, repository path. When the CLI is installed as 'git-credential-credhub', git can use it with 'git config credential.helper credhub'."`
+	Get              GetCommand              `command:"get"        alias:"g" description:"Get a credential value" long-description:"Get a credential value by name or ID. Several credentials may be retrieved at once by repeating --name or with --path, optionally including credentials in nested paths with --recursive; they are printed as a single map keyed by name.\n\n More information: https://credhub-api.cfapps.io/#get-credentials"`
+	Import           ImportCommand           `command:"import"     alias:"i" description:"Set multiple credential values" long-description:"Set multiple credential values from import file. File must be in yaml format containing a list of credentials under the key 'credentials'. Name, type and value are required for each credential in the list.\n\n More information: https://credhub-api.cfapps.io/#bulk-import"`
+	Login            LoginCommand            `command:"login"      alias:"l" description:"Authenticate with CredHub" long-description:"Authenticate with CredHub. UAA password and client credential grants are supported. If client credentials exist in the environment, authentication will be performed automatically without the need to explicitly call this command."`
+	Logout           LogoutCommand           `command:"logout"     alias:"o" description:"Discard authenticated user session" long-description:"Discard authenticated session. Refresh token revocation will be attempted for password grants."`
+	Move             MoveCommand             `command:"move"       description:"Move or rename a credential or all credentials under a path" long-description:"Copy a credential to a new name, or every credential under a path to the same relative names under a new path, and then delete the originals. With --all-versions the full history is replayed oldest first. Certificates that reference a moved CA by ca_name are updated to reference its new name."`
+	Patch            PatchCommand            `command:"patch"      description:"Change individual keys of a json credential" long-description:"Apply a JSON merge patch (RFC 7386) with --merge, or JSON patch operations (RFC 6902) with --json-patch, to the current value of a json credential and set the result as a new version."`
+	Proxy            ProxyCommand            `command:"proxy"      description:"Forward local HTTP requests to CredHub with authentication" long-description:"Start an HTTP server on localhost that forwards requests to the targeted CredHub server, attaching a bearer token and refreshing it as needed. Use --allow-path and --read-only to limit what can be reached through the proxy."`
+	Regenerate       RegenerateCommand       `command:"regenerate" alias:"r" description:"Generate and set a credential value using the same attributes as the stored value" long-description:"Set a credential with a generated value using the same attributes as the stored value.\n\n More information: https://credhub-api.cfapps.io/#regenerate-credentials"`
+	Rollback         RollbackCommand         `command:"rollback"   description:"Restore a previous version of a credential" long-description:"Set the value of a previous version of a credential as its new current version, preserving its type. Select the version with --steps (1 by default) or --to-id. The difference from the current version is shown and must be confirmed unless --force is given."`
+	Set              SetCommand              `command:"set"        alias:"s" description:"Set a credential with a provided value" long-description:"Set a credential with provided value(s). A type must be specified when setting a credential. The provided flags are used to set specific values of a credential, e.g. a certificate credential may use --root, --certificate and --private to set each value. Supported credential types are prefixed in the flag description.\n\n More information: https://credhub-api.cfapps.io/#set-credentials"`
+	Sync             SyncCommand             `command:"sync"       description:"Copy credentials under a path from one profile to another" long-description:"Compare the credentials under a path on two profiles and create, update or, with --delete-extraneous, delete credentials on the destination so that it matches the source. The change plan is printed before any change is made."`
+	Target           TargetCommand           `command:"target"     alias:"t" description:"Manage named target profiles" long-description:"Manage named target profiles. Each profile stores its own API target, trusted CAs and authentication tokens. The active profile is selected with 'credhub target use', the CREDHUB_PROFILE environment variable or the --profile flag."`
+	VersionCmd       VersionCommand          `command:"version"    description:"Version of CLI and targeted CredHub API" long-description:"Print the version of the CLI and of the targeted CredHub server. With --capabilities, also list the API features the server supports, which determine how the CLI talks to it."`
 
-	Version    func()            `long:"version" description:"Version of CLI and targeted CredHub API"`
-	Token      func()            `long:"token" description:"Return your current CredHub authentication token"`
+	Version    func()              `long:"version" description:"Version of CLI and targeted CredHub API"`
+	Token      func()              `long:"token" description:"Return your current CredHub authentication token"`
+	Timeout    func(time.Duration) `long:"timeout" description:"Time limit for requests to the CredHub and auth servers, e.g. 90s (Default: 45s)" env:"CREDHUB_TIMEOUT"`
+	Profile    func(string)        `long:"profile" description:"Name of the target profile to use for this command" env:"CREDHUB_PROFILE"`
+	OutputJson bool                `long:"output-json" description:"Return responses and errors in JSON format"`
 }
 
 var CredHub CredhubCommand
@@ -2,16 +2,102 @@ package commands
 
 import (
 	"fmt"
+	"regexp"
+	"sort"
+	"strings"
 
 	"github.com/cloudfoundry-incubator/credhub-cli/config"
+	"github.com/cloudfoundry-incubator/credhub-cli/credhub"
+	"github.com/cloudfoundry-incubator/credhub-cli/errors"
 )
 
 type DeleteCommand struct {
-	CredentialIdentifier string `short:"n" long:"name" required:"yes" description:"Name of the credential to delete"`
+	CredentialIdentifier string `short:"n" long:"name" description:"Name of the credential to delete"`
+	Path                 string `short:"p" long:"path" description:"Delete the credentials directly under this path"`
+	Recursive            bool   `short:"r" long:"recursive" description:"Also delete credentials in paths below --path"`
+	NameLike             string `long:"name-like" description:"Delete credentials whose names contain this string"`
+	Regex                string `long:"regex" description:"Delete credentials whose names match this regular expression"`
+	Force                bool   `long:"force" description:"Delete matched credentials without asking for confirmation"`
+	DryRun               bool   `long:"dry-run" description:"List the matched credentials without deleting them"`
 }
 
 func (cmd DeleteCommand) Execute([]string) error {
-	cfg := config.ReadConfig()
+	if cmd.Path == "" && cmd.NameLike == "" && cmd.Regex == "" {
+		if cmd.CredentialIdentifier == "" {
+			return errors.NewMissingDeleteParametersError()
+		}
+		if cmd.DryRun {
+			fmt.Println("The following credentials will be deleted:")
+			fmt.Println("  " + cmd.CredentialIdentifier)
+			fmt.Println("Dry run: no credentials were deleted.")
+			return nil
+		}
+		return cmd.deleteOne()
+	}
+
+	var pattern *regexp.Regexp
+	if cmd.Regex != "" {
+		var err error
+		if pattern, err = regexp.Compile(cmd.Regex); err != nil {
+			return errors.NewInvalidDeleteRegexError(err)
+		}
+	}
+
+	cfg, err := config.ReadConfig()
+	if err != nil {
+		return err
+	}
+	credhubClient, err := initializeCredhubClient(cfg)
+	if err != nil {
+		return err
+	}
+
+	names, err := cmd.matches(credhubClient, pattern)
+	if err != nil {
+		return err
+	}
+
+	if len(names) == 0 {
+		fmt.Println("No credentials matched.")
+		return nil
+	}
+
+	fmt.Println("The following credentials will be deleted:")
+	for _, name := range names {
+		fmt.Println("  " + name)
+	}
+
+	if cmd.DryRun {
+		fmt.Println("Dry run: no credentials were deleted.")
+		return nil
+	}
+
+	if !cmd.Force {
+		var answer string
+		promptForInput(fmt.Sprintf("Delete %d credential(s)? [y/N]: ", len(names)), &answer)
+		if answer = strings.ToLower(answer); answer != "y" && answer != "yes" {
+			fmt.Println("Delete cancelled.")
+			return nil
+		}
+	}
+
+	for i, name := range names {
+		if err := credhubClient.Delete(name); err != nil {
+			fmt.Printf("Deleted %d of %d credential(s) before failing to delete %s.\n", i, len(names), name)
+			return err
+		}
+	}
+
+	fmt.Printf("Deleted %d credential(s).\n", len(names))
+
+	return nil
+}
+
+func (cmd DeleteCommand) deleteOne() error {
+	cfg, err := config.ReadConfig()
+	if err != nil {
+		return err
+	}
 	credhubClient, err := initializeCredhubClient(cfg)
 
 	if err != nil {
@@ -26,3 +112,52 @@ func (cmd DeleteCommand) Execute([]string) error {
 
 	return err
 }
+
+// matches returns the names selected by --path, --name-like and --regex.
+// Every selector given must match, and --name is always included.
+func (cmd DeleteCommand) matches(credhubClient *credhub.CredHub, pattern *regexp.Regexp) ([]string, error) {
+	var candidates []string
+
+	switch {
+	case cmd.Path != "":
+		names, err := namesUnderPath(credhubClient, cmd.Path, cmd.Recursive)
+		if err != nil {
+			return nil, err
+		}
+		candidates = names
+	case cmd.NameLike != "":
+		results, err := credhubClient.FindByPartialName(cmd.NameLike)
+		if err != nil {
+			return nil, err
+		}
+		for _, result := range results.Credentials {
+			candidates = append(candidates, result.Name)
+		}
+	default:
+		names, err := namesUnderPath(credhubClient, "/", true)
+		if err != nil {
+			return nil, err
+		}
+		candidates = names
+	}
+
+	var names []string
+	if cmd.CredentialIdentifier != "" {
+		names = append(names, cmd.CredentialIdentifier)
+	}
+
+	for _, name := range candidates {
+		if cmd.NameLike != "" && !strings.Contains(strings.ToLower(name), strings.ToLower(cmd.NameLike)) {
+			continue
+		}
+		if pattern != nil && !pattern.MatchString(name) {
+			continue
+		}
+		names = append(names, name)
+	}
+
+	names = uniqueNames(names)
+	sort.Strings(names)
+
+	return names, nil
+}
@@ -2,8 +2,7 @@ package commands_test
 
 import (
 	"net/http"
-
-	"runtime"
+	"strings"
 
 	"github.com/cloudfoundry-incubator/credhub-cli/config"
 	. "github.com/onsi/ginkgo"
@@ -20,7 +19,7 @@ var _ = Describe("Delete", func() {
 
 	ItRequiresAuthentication("delete", "-n", "test-credential")
 	ItRequiresAnAPIToBeSet("delete", "-n", "test-credential")
-	ItAutomaticallyLogsIn("DELETE", "delete_test.json", "/api/v1/data", "delete", "-n", "test-credential")
+	ItAutomaticallyLogsIn("delete", "delete", "-n", "test-credential")
 
 	Describe("Help", func() {
 		ItBehavesLikeHelp("delete", "d", func(session *Session) {
@@ -45,26 +44,93 @@ var _ = Describe("Delete", func() {
 
 	Describe("Errors", func() {
 		It("prints an error when the network request fails", func() {
-			cfg := config.ReadConfig()
+			cfg, _ := config.ReadConfig()
 			cfg.ApiURL = "mashed://potatoes"
 			config.WriteConfig(cfg)
 
 			session := runCommand("delete", "-n", "my-secret")
 
-			Eventually(session).Should(Exit(1))
+			Eventually(session).Should(Exit(7))
 			Eventually(string(session.Err.Contents())).Should(ContainSubstring("Delete mashed://potatoes/api/v1/data?name=my-secret: unsupported protocol scheme \"mashed\""))
 		})
 
 		It("displays missing required parameter", func() {
 			session := runCommand("delete")
 
-			Eventually(session).Should(Exit(1))
+			Eventually(session).Should(Exit(2))
+
+			Expect(session.Err).To(Say("A name, path, name-like or regex must be provided. Please update and retry your request."))
+		})
+	})
+
+	Describe("multiple credentials", func() {
+		var store *credentialStore
+
+		BeforeEach(func() {
+			store = newCredentialStore(server, map[string]string{
+				"/dep/password":        valueCredential("password", "/dep/password", "secret"),
+				"/dep/job/certificate": valueCredential("value", "/dep/job/certificate", "cert"),
+				"/other/password":      valueCredential("password", "/other/password", "secret"),
+			})
+		})
+
+		It("lists the credentials under a path without deleting them on a dry run", func() {
+			session := runCommand("delete", "-p", "/dep", "--recursive", "--dry-run")
+
+			Eventually(session).Should(Exit(0))
+			Expect(session.Out).To(Say("The following credentials will be deleted:\n  /dep/job/certificate\n  /dep/password\n"))
+			Expect(session.Out).To(Say("Dry run: no credentials were deleted."))
+			Expect(store.deletes).To(BeEmpty())
+		})
+
+		It("does not delete a credential given by name on a dry run", func() {
+			session := runCommand("delete", "-n", "/dep/password", "--dry-run")
+
+			Eventually(session).Should(Exit(0))
+			Expect(session.Out).To(Say("The following credentials will be deleted:\n  /dep/password\n"))
+			Expect(session.Out).To(Say("Dry run: no credentials were deleted."))
+			Expect(store.deletes).To(BeEmpty())
+		})
+
+		It("deletes the credentials directly under a path after confirmation", func() {
+			session := runCommandWithStdin(strings.NewReader("yes\n"), "delete", "-p", "/dep")
+
+			Eventually(session).Should(Exit(0))
+			Expect(session.Out).To(Say(`Delete 1 credential\(s\)\? \[y/N\]: `))
+			Expect(session.Out).To(Say(`Deleted 1 credential\(s\).`))
+			Expect(store.deletes).To(Equal([]string{"/dep/password"}))
+		})
+
+		It("does not delete anything when not confirmed", func() {
+			session := runCommandWithStdin(strings.NewReader("\n"), "delete", "-p", "/dep", "-r")
+
+			Eventually(session).Should(Exit(0))
+			Expect(session.Out).To(Say("Delete cancelled."))
+			Expect(store.deletes).To(BeEmpty())
+		})
+
+		It("deletes credentials matching a partial name and a regex when forced", func() {
+			session := runCommand("delete", "--name-like", "password", "--regex", "^/dep/", "--force")
+
+			Eventually(session).Should(Exit(0))
+			Expect(session.Out).To(Say("  /dep/password\n"))
+			Expect(session.Out).To(Say(`Deleted 1 credential\(s\).`))
+			Expect(store.deletes).To(Equal([]string{"/dep/password"}))
+		})
+
+		It("reports when nothing matches", func() {
+			session := runCommand("delete", "--regex", "^/missing/", "--force")
+
+			Eventually(session).Should(Exit(0))
+			Expect(session.Out).To(Say("No credentials matched."))
+			Expect(store.deletes).To(BeEmpty())
+		})
+
+		It("rejects an invalid regex", func() {
+			session := runCommand("delete", "--regex", "(", "--force")
 
-			if runtime.GOOS == "windows" {
-				Expect(session.Err).To(Say("the required flag `/n, /name' was not specified"))
-			} else {
-				Expect(session.Err).To(Say("the required flag `-n, --name' was not specified"))
-			}
+			Eventually(session).Should(Exit(2))
+			Expect(session.Err).To(Say("The regex could not be parsed"))
 		})
 	})
 })
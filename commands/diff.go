@@ -0,0 +1,307 @@
+package commands
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"path/filepath"
+
+	"github.com/cloudfoundry-incubator/credhub-cli/config"
+	"github.com/cloudfoundry-incubator/credhub-cli/credhub"
+	"github.com/cloudfoundry-incubator/credhub-cli/credhub/credentials"
+	"github.com/cloudfoundry-incubator/credhub-cli/errors"
+	"github.com/cloudfoundry-incubator/credhub-cli/models"
+	"github.com/cloudfoundry-incubator/credhub-cli/util"
+	"gopkg.in/yaml.v2"
+)
+
+type DiffCommand struct {
+	Name        string `short:"n" long:"name" description:"Name of the credential whose versions to compare"`
+	FromId      string `long:"from-id" description:"ID of the version to compare from (defaults to the previous version of --name)"`
+	ToId        string `long:"to-id" description:"ID of the version to compare to (defaults to the current version of --name)"`
+	Path        string `short:"p" long:"path" description:"Path of the credentials to compare"`
+	File        string `short:"f" long:"file" description:"Export file to compare the credentials under --path against"`
+	FromProfile string `long:"from-profile" description:"Profile to compare --path from (defaults to the active profile)"`
+	ToProfile   string `long:"to-profile" description:"Profile to compare --path against"`
+	Mask        bool   `long:"mask" description:"Hide secret values, only showing which fields changed"`
+}
+
+// diffSide holds the credentials on one side of a diff, keyed by name.
+type diffSide struct {
+	label       string
+	credentials map[string]credentials.Credential
+}
+
+// secretFields lists the fields of structured credential types that --mask
+// hides. Values of other types are hidden entirely.
+var secretFields = map[string][]string{
+	"user":        {"password"},
+	"certificate": {"private_key"},
+	"rsa":         {"private_key"},
+	"ssh":         {"private_key"},
+}
+
+func (cmd DiffCommand) Execute([]string) error {
+	var from, to diffSide
+	var err error
+
+	switch {
+	case cmd.Path != "":
+		from, to, err = cmd.pathSides()
+	case cmd.Name != "" || (cmd.FromId != "" && cmd.ToId != ""):
+		from, to, err = cmd.versionSides()
+	default:
+		return errors.NewMissingDiffParametersError()
+	}
+	if err != nil {
+		return err
+	}
+
+	var mask func(interface{}) string
+	if cmd.Mask {
+		mask = newMasker()
+	}
+
+	differences := false
+	for _, name := range sortedNames(mergeCredentials(from.credentials, to.credentials)) {
+		hunks := util.UnifiedDiff(diffDocument(from.credentials, name, mask), diffDocument(to.credentials, name, mask))
+		if hunks == "" {
+			continue
+		}
+
+		differences = true
+		fmt.Printf("--- %s (%s)\n+++ %s (%s)\n%s", name, from.label, name, to.label, hunks)
+	}
+
+	if !differences {
+		fmt.Println("No differences found.")
+	}
+
+	return nil
+}
+
+func (cmd DiffCommand) versionSides() (diffSide, diffSide, error) {
+	cfg, err := config.ReadConfig()
+	if err != nil {
+		return diffSide{}, diffSide{}, err
+	}
+
+	credhubClient, err := initializeCredhubClient(cfg)
+	if err != nil {
+		return diffSide{}, diffSide{}, err
+	}
+
+	var versions []credentials.Credential
+	if cmd.FromId == "" || cmd.ToId == "" {
+		versions, err = credhubClient.GetNVersions(cmd.Name, 2)
+		if err != nil {
+			return diffSide{}, diffSide{}, err
+		}
+	}
+
+	to, err := versionSide(credhubClient, cmd.ToId, versions, 0)
+	if err != nil {
+		return diffSide{}, diffSide{}, err
+	}
+
+	from, err := versionSide(credhubClient, cmd.FromId, versions, 1)
+	if err != nil {
+		return diffSide{}, diffSide{}, err
+	}
+
+	// Versions fetched by ID may belong to different credentials, so compare
+	// them under a single name.
+	name := cmd.Name
+	if name == "" {
+		for credentialName := range to.credentials {
+			name = credentialName
+		}
+	}
+
+	return renameSide(from, name), renameSide(to, name), nil
+}
+
+// versionSide returns the version with the given id, or versions[index] of
+// the most recent versions when id is empty.
+func versionSide(credhubClient *credhub.CredHub, id string, versions []credentials.Credential, index int) (diffSide, error) {
+	side := diffSide{credentials: map[string]credentials.Credential{}}
+
+	if id != "" {
+		credential, err := credhubClient.GetById(id)
+		if err != nil {
+			return side, err
+		}
+		versions = []credentials.Credential{credential}
+		index = 0
+	}
+
+	if index < len(versions) {
+		side.label = "version " + versions[index].Id
+		side.credentials[versions[index].Name] = versions[index]
+	} else {
+		side.label = "no previous version"
+	}
+
+	return side, nil
+}
+
+func renameSide(side diffSide, name string) diffSide {
+	renamed := map[string]credentials.Credential{}
+	for _, credential := range side.credentials {
+		renamed[name] = credential
+	}
+	side.credentials = renamed
+
+	return side
+}
+
+func (cmd DiffCommand) pathSides() (diffSide, diffSide, error) {
+	if (cmd.File == "") == (cmd.ToProfile == "") {
+		return diffSide{}, diffSide{}, errors.NewMissingDiffParametersError()
+	}
+
+	from, err := profilePathSide(cmd.FromProfile, cmd.Path)
+	if err != nil {
+		return diffSide{}, diffSide{}, err
+	}
+
+	if cmd.ToProfile != "" {
+		to, err := profilePathSide(cmd.ToProfile, cmd.Path)
+		return from, to, err
+	}
+
+	to, err := fileSide(cmd.File)
+	return from, to, err
+}
+
+func profilePathSide(profile, path string) (diffSide, error) {
+	if profile == "" {
+		profiles, err := config.ReadProfiles()
+		if err != nil {
+			return diffSide{}, err
+		}
+		profile = profiles.ActiveProfile()
+	}
+
+	credhubClient, err := profileCredhubClient(profile)
+	if err != nil {
+		return diffSide{}, err
+	}
+
+	latest, err := latestVersionsByPath(credhubClient, path)
+	if err != nil {
+		return diffSide{}, err
+	}
+
+	return diffSide{label: "profile " + profile, credentials: latest}, nil
+}
+
+func fileSide(file string) (diffSide, error) {
+	var export models.CredentialBulkImport
+	if err := export.ReadFile(file); err != nil {
+		return diffSide{}, err
+	}
+
+	side := diffSide{label: filepath.Base(file), credentials: map[string]credentials.Credential{}}
+	for _, credential := range export.Credentials {
+		name, _ := credential["name"].(string)
+		credType, _ := credential["type"].(string)
+
+		side.credentials[name] = credentials.Credential{
+			Metadata: credentials.Metadata{Base: credentials.Base{Name: name}, Type: credType},
+			Value:    credential["value"],
+		}
+	}
+
+	return side, nil
+}
+
+func mergeCredentials(a, b map[string]credentials.Credential) map[string]credentials.Credential {
+	merged := map[string]credentials.Credential{}
+	for name, credential := range a {
+		merged[name] = credential
+	}
+	for name, credential := range b {
+		merged[name] = credential
+	}
+
+	return merged
+}
+
+// diffDocument renders the type and value of the named credential as YAML,
+// or an empty document when it does not exist. Secrets are replaced with
+// mask when it is not nil.
+func diffDocument(credentialsByName map[string]credentials.Credential, name string, mask func(interface{}) string) string {
+	credential, ok := credentialsByName[name]
+	if !ok {
+		return ""
+	}
+
+	value := settableValue(credential)
+	if mask != nil {
+		value = maskValue(credential.Type, value, mask)
+	}
+
+	document, _ := yaml.Marshal(yaml.MapSlice{
+		{Key: "type", Value: credential.Type},
+		{Key: "value", Value: value},
+	})
+
+	return string(document)
+}
+
+func maskValue(credType string, value interface{}, mask func(interface{}) string) interface{} {
+	fields, ok := secretFields[credType]
+	structured, isMap := value.(map[string]interface{})
+	if !ok || !isMap {
+		return maskAll(value, mask)
+	}
+
+	masked := map[string]interface{}{}
+	for k, v := range structured {
+		masked[k] = v
+	}
+	for _, field := range fields {
+		if v, ok := masked[field]; ok && v != nil {
+			masked[field] = mask(v)
+		}
+	}
+
+	return masked
+}
+
+func maskAll(value interface{}, mask func(interface{}) string) interface{} {
+	switch typed := value.(type) {
+	case map[string]interface{}:
+		masked := map[string]interface{}{}
+		for k, v := range typed {
+			masked[k] = maskAll(v, mask)
+		}
+		return masked
+	case []interface{}:
+		masked := make([]interface{}, len(typed))
+		for i, v := range typed {
+			masked[i] = maskAll(v, mask)
+		}
+		return masked
+	default:
+		return mask(value)
+	}
+}
+
+// newMasker returns a function that replaces a value with a short keyed
+// hash. Equal values mask to the same string within one invocation, so
+// changes remain visible, but the key is discarded so values cannot be
+// recovered from the output.
+func newMasker() func(interface{}) string {
+	key := make([]byte, 32)
+	rand.Read(key)
+
+	return func(value interface{}) string {
+		h := hmac.New(sha256.New, key)
+		fmt.Fprint(h, value)
+		return "<redacted:" + hex.EncodeToString(h.Sum(nil))[:8] + ">"
+	}
+}
@@ -0,0 +1,196 @@
+package commands_test
+
+import (
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gbytes"
+	. "github.com/onsi/gomega/gexec"
+	. "github.com/onsi/gomega/ghttp"
+)
+
+func versionedCredential(id, credType, name, value string) string {
+	return `{"type":"` + credType + `","id":"` + id + `","name":"` + name + `","version_created_at":"` + TIMESTAMP + `","value":` + value + `}`
+}
+
+var _ = Describe("Diff", func() {
+	BeforeEach(func() {
+		login()
+	})
+
+	ItRequiresAuthentication("diff", "-n", "test-credential")
+
+	It("requires a name, version IDs or a path", func() {
+		session := runCommand("diff")
+
+		Eventually(session).Should(Exit(2))
+		Expect(session.Err).To(Say("A name, two version IDs, or a path with either a file or a profile to compare against must be provided."))
+	})
+
+	Describe("versions", func() {
+		It("compares the current version with the previous one", func() {
+			server.AppendHandlers(
+				CombineHandlers(
+					VerifyRequest("GET", "/api/v1/data", "name=%2Fmy-user&versions=2"),
+					RespondWith(http.StatusOK, `{"data":[`+
+						versionedCredential("new-id", "user", "/my-user", `{"username":"admin","password":"new-password","password_hash":"new-hash"}`)+`,`+
+						versionedCredential("old-id", "user", "/my-user", `{"username":"admin","password":"old-password","password_hash":"old-hash"}`)+`]}`),
+				),
+			)
+
+			session := runCommand("diff", "-n", "/my-user")
+
+			Eventually(session).Should(Exit(0))
+			Expect(session.Out.Contents()).To(ContainSubstring(`--- /my-user (version old-id)
++++ /my-user (version new-id)
+@@ -1,4 +1,4 @@
+ type: user
+ value:
+-  password: old-password
++  password: new-password
+   username: admin
+`))
+		})
+
+		It("shows a credential with a single version as created", func() {
+			server.AppendHandlers(
+				RespondWith(http.StatusOK, `{"data":[`+versionedCredential("only-id", "value", "/my-value", `"potatoes"`)+`]}`),
+			)
+
+			session := runCommand("diff", "-n", "/my-value")
+
+			Eventually(session).Should(Exit(0))
+			Expect(session.Out).To(Say(`--- /my-value \(no previous version\)`))
+			Expect(session.Out).To(Say(`\+\+\+ /my-value \(version only-id\)`))
+			Expect(session.Out).To(Say(`@@ -0,0 \+1,2 @@`))
+			Expect(session.Out).To(Say(`\+value: potatoes`))
+		})
+
+		It("compares versions by ID", func() {
+			server.AppendHandlers(
+				CombineHandlers(
+					VerifyRequest("GET", "/api/v1/data/second-id"),
+					RespondWith(http.StatusOK, versionedCredential("second-id", "value", "/my-value", `"new"`)),
+				),
+				CombineHandlers(
+					VerifyRequest("GET", "/api/v1/data/first-id"),
+					RespondWith(http.StatusOK, versionedCredential("first-id", "value", "/my-value", `"old"`)),
+				),
+			)
+
+			session := runCommand("diff", "--from-id", "first-id", "--to-id", "second-id")
+
+			Eventually(session).Should(Exit(0))
+			Expect(session.Out).To(Say(`--- /my-value \(version first-id\)`))
+			Expect(session.Out).To(Say(`\+\+\+ /my-value \(version second-id\)`))
+			Expect(session.Out).To(Say(`-value: old`))
+			Expect(session.Out).To(Say(`\+value: new`))
+		})
+
+		It("masks secret values", func() {
+			server.AppendHandlers(
+				RespondWith(http.StatusOK, `{"data":[`+
+					versionedCredential("new-id", "user", "/my-user", `{"username":"admin","password":"new-password"}`)+`,`+
+					versionedCredential("old-id", "user", "/my-user", `{"username":"admin","password":"old-password"}`)+`]}`),
+			)
+
+			session := runCommand("diff", "-n", "/my-user", "--mask")
+
+			Eventually(session).Should(Exit(0))
+			Expect(session.Out).To(Say(`-  password: <redacted:[0-9a-f]{8}>`))
+			Expect(session.Out).To(Say(`\+  password: <redacted:[0-9a-f]{8}>`))
+			Expect(session.Out).To(Say(`   username: admin`))
+			Expect(session.Out).NotTo(Say(`password: (old|new)-password`))
+		})
+
+		It("reports when there are no differences", func() {
+			server.AppendHandlers(
+				RespondWith(http.StatusOK, `{"data":[`+
+					versionedCredential("new-id", "value", "/my-value", `"same"`)+`,`+
+					versionedCredential("old-id", "value", "/my-value", `"same"`)+`]}`),
+			)
+
+			session := runCommand("diff", "-n", "/my-value")
+
+			Eventually(session).Should(Exit(0))
+			Expect(session.Out).To(Say(`No differences found.`))
+		})
+	})
+
+	Describe("paths", func() {
+		var exportFile string
+
+		BeforeEach(func() {
+			newCredentialStore(server, map[string]string{
+				"/deploy/same":    valueCredential("value", "/deploy/same", "same-value"),
+				"/deploy/changed": valueCredential("password", "/deploy/changed", "current-password"),
+				"/deploy/removed": valueCredential("value", "/deploy/removed", "removed-value"),
+			})
+
+			dir, err := ioutil.TempDir("", "credhub-diff")
+			Expect(err).NotTo(HaveOccurred())
+			exportFile = filepath.Join(dir, "export.yml")
+			Expect(ioutil.WriteFile(exportFile, []byte(`credentials:
+- name: /deploy/same
+  type: value
+  value: same-value
+- name: /deploy/changed
+  type: password
+  value: exported-password
+- name: /deploy/added
+  type: value
+  value: added-value
+`), 0600)).To(Succeed())
+		})
+
+		AfterEach(func() {
+			os.RemoveAll(filepath.Dir(exportFile))
+		})
+
+		It("compares the credentials under a path with an export file", func() {
+			session := runCommand("diff", "-p", "/deploy", "-f", exportFile)
+
+			Eventually(session).Should(Exit(0))
+			Expect(session.Out).To(Say(`--- /deploy/added \(profile default\)\n\+\+\+ /deploy/added \(export.yml\)\n@@ -0,0 \+1,2 @@\n\+type: value\n\+value: added-value\n`))
+			Expect(session.Out).To(Say(`--- /deploy/changed \(profile default\)\n\+\+\+ /deploy/changed \(export.yml\)\n@@ -1,2 \+1,2 @@\n type: password\n-value: current-password\n\+value: exported-password\n`))
+			Expect(session.Out).To(Say(`--- /deploy/removed \(profile default\)\n\+\+\+ /deploy/removed \(export.yml\)\n@@ -1,2 \+0,0 @@\n-type: value\n-value: removed-value\n`))
+			Expect(session.Out.Contents()).NotTo(ContainSubstring("/deploy/same"))
+		})
+
+		It("compares the credentials under a path on two profiles", func() {
+			otherServer := NewTlsServer("../test/server-tls-cert.pem", "../test/server-tls-key.pem")
+			defer otherServer.Close()
+			SetupServers(otherServer, authServer)
+			Eventually(runCommand("target", "add", "dr", "-s", otherServer.URL(), "--ca-cert", "../test/server-tls-ca.pem", "--ca-cert", "../test/auth-tls-ca.pem")).Should(Exit(0))
+
+			authServer.AppendHandlers(
+				RespondWith(http.StatusOK, `{"access_token":"dr-access-token","refresh_token":"dr-refresh-token","token_type":"password","expires_in":123456789}`),
+			)
+			Eventually(runCommand("--profile", "dr", "login", "-u", "test-username", "-p", "test-password")).Should(Exit(0))
+
+			newCredentialStore(otherServer, map[string]string{
+				"/deploy/same":    valueCredential("value", "/deploy/same", "same-value"),
+				"/deploy/changed": valueCredential("password", "/deploy/changed", "dr-password"),
+				"/deploy/removed": valueCredential("value", "/deploy/removed", "removed-value"),
+			})
+
+			session := runCommand("diff", "-p", "/deploy", "--from-profile", "default", "--to-profile", "dr")
+
+			Eventually(session).Should(Exit(0))
+			Expect(session.Out).To(Say(`--- /deploy/changed \(profile default\)\n\+\+\+ /deploy/changed \(profile dr\)\n`))
+			Expect(session.Out).To(Say(`-value: current-password\n\+value: dr-password\n`))
+			Expect(session.Out.Contents()).NotTo(ContainSubstring("/deploy/removed"))
+		})
+
+		It("requires either a file or a profile to compare against", func() {
+			session := runCommand("diff", "-p", "/deploy")
+
+			Eventually(session).Should(Exit(2))
+			Expect(session.Err).To(Say("A name, two version IDs, or a path with either a file or a profile to compare against must be provided."))
+		})
+	})
+})
@@ -0,0 +1,213 @@
+package commands
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/cloudfoundry-incubator/credhub-cli/config"
+	"github.com/cloudfoundry-incubator/credhub-cli/credhub"
+	"github.com/cloudfoundry-incubator/credhub-cli/credhub/credentials/values"
+	"github.com/cloudfoundry-incubator/credhub-cli/errors"
+)
+
+// DockerCredentialHelperName is the executable name Docker looks up for a
+// "credhub" credsStore. When invoked under this name the CLI behaves as
+// `credhub docker-credential`.
+const DockerCredentialHelperName = "docker-credential-credhub"
+
+const dockerCredentialsNotFound = "credentials not found in native keychain"
+
+type DockerCredentialCommand struct {
+	Path string                     `long:"path" description:"Path under which registry credentials are stored" env:"CREDHUB_DOCKER_CREDENTIAL_PATH" default:"/docker-credentials"`
+	Args DockerCredentialActionArgs `positional-args:"yes" required:"yes"`
+}
+
+type DockerCredentialActionArgs struct {
+	Action string `positional-arg-name:"ACTION" required:"yes" description:"One of get, store, erase or list"`
+}
+
+type dockerCredential struct {
+	ServerURL string
+	Username  string
+	Secret    string
+}
+
+func (cmd DockerCredentialCommand) Execute([]string) error {
+	switch cmd.Args.Action {
+	case "get", "store", "erase", "list":
+	default:
+		return errors.NewUnknownHelperActionError(cmd.Args.Action, "get, store, erase or list")
+	}
+
+	cfg, err := config.ReadConfig()
+	if err != nil {
+		return err
+	}
+
+	credhubClient, err := initializeCredhubClient(cfg)
+	if err != nil {
+		return err
+	}
+
+	switch cmd.Args.Action {
+	case "get":
+		return cmd.get(credhubClient, os.Stdin, os.Stdout)
+	case "store":
+		return cmd.store(credhubClient, os.Stdin)
+	case "erase":
+		return cmd.erase(credhubClient, os.Stdin)
+	default:
+		return cmd.list(credhubClient, os.Stdout)
+	}
+}
+
+func (cmd DockerCredentialCommand) get(credhubClient *credhub.CredHub, in io.Reader, out io.Writer) error {
+	serverURL, err := readServerURL(in)
+	if err != nil {
+		return err
+	}
+
+	user, err := credhubClient.GetLatestUser(dockerCredentialName(cmd.Path, serverURL))
+	if err != nil {
+		if isNotFoundError(err) {
+			fmt.Fprintln(out, dockerCredentialsNotFound)
+			return errors.NewDockerCredentialsNotFoundError()
+		}
+		return err
+	}
+
+	credential := dockerCredential{
+		ServerURL: serverURL,
+		Secret:    user.Value.Password,
+	}
+	if user.Value.Username != nil {
+		credential.Username = *user.Value.Username
+	}
+
+	return json.NewEncoder(out).Encode(credential)
+}
+
+func (cmd DockerCredentialCommand) store(credhubClient *credhub.CredHub, in io.Reader) error {
+	var credential dockerCredential
+	if err := json.NewDecoder(in).Decode(&credential); err != nil {
+		return err
+	}
+
+	if credential.ServerURL == "" {
+		return errors.NewMissingServerURLError()
+	}
+
+	value := values.User{
+		Username: &credential.Username,
+		Password: credential.Secret,
+	}
+
+	_, err := credhubClient.SetUser(dockerCredentialName(cmd.Path, credential.ServerURL), value, true)
+	return err
+}
+
+func (cmd DockerCredentialCommand) erase(credhubClient *credhub.CredHub, in io.Reader) error {
+	serverURL, err := readServerURL(in)
+	if err != nil {
+		return err
+	}
+
+	return credhubClient.Delete(dockerCredentialName(cmd.Path, serverURL))
+}
+
+func (cmd DockerCredentialCommand) list(credhubClient *credhub.CredHub, out io.Writer) error {
+	results, err := credhubClient.FindByPath(cmd.Path)
+	if err != nil {
+		return err
+	}
+
+	serverURLs := map[string]string{}
+
+	for _, result := range results.Credentials {
+		serverURL, ok := dockerServerURL(cmd.Path, result.Name)
+		if !ok {
+			continue
+		}
+
+		user, err := credhubClient.GetLatestUser(result.Name)
+		if err != nil {
+			return err
+		}
+
+		username := ""
+		if user.Value.Username != nil {
+			username = *user.Value.Username
+		}
+
+		serverURLs[serverURL] = username
+	}
+
+	return json.NewEncoder(out).Encode(serverURLs)
+}
+
+func readServerURL(in io.Reader) (string, error) {
+	data, err := ioutil.ReadAll(in)
+	if err != nil {
+		return "", err
+	}
+
+	serverURL := strings.TrimSpace(string(data))
+	if serverURL == "" {
+		return "", errors.NewMissingServerURLError()
+	}
+
+	return serverURL, nil
+}
+
+// dockerCredentialName maps a registry server URL to a credential name under
+// path. Every character other than a letter, digit, '.' or '-' is escaped as
+// '_' followed by its hex code, so that the server URL can be recovered from
+// the name, e.g. https://registry.example.com:5000 becomes
+// <path>/https_3A_2F_2Fregistry.example.com_3A5000.
+func dockerCredentialName(path, serverURL string) string {
+	var name bytes.Buffer
+	for i := 0; i < len(serverURL); i++ {
+		c := serverURL[i]
+		if 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || '0' <= c && c <= '9' || c == '.' || c == '-' {
+			name.WriteByte(c)
+		} else {
+			fmt.Fprintf(&name, "_%02X", c)
+		}
+	}
+
+	return strings.TrimRight(path, "/") + "/" + name.String()
+}
+
+// dockerServerURL returns the server URL that dockerCredentialName maps to
+// name, or false when name was not stored by the helper.
+func dockerServerURL(path, name string) (string, bool) {
+	escaped := strings.TrimPrefix(name, strings.TrimRight(path, "/")+"/")
+	if strings.Contains(escaped, "/") {
+		return "", false
+	}
+
+	var serverURL bytes.Buffer
+	for i := 0; i < len(escaped); i++ {
+		if escaped[i] != '_' {
+			serverURL.WriteByte(escaped[i])
+			continue
+		}
+		if i+3 > len(escaped) {
+			return "", false
+		}
+		c, err := strconv.ParseUint(escaped[i+1:i+3], 16, 8)
+		if err != nil {
+			return "", false
+		}
+		serverURL.WriteByte(byte(c))
+		i += 2
+	}
+
+	return serverURL.String(), true
+}
@@ -0,0 +1,208 @@
+package commands_test
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/cloudfoundry-incubator/credhub-cli/commands"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gbytes"
+	. "github.com/onsi/gomega/gexec"
+	. "github.com/onsi/gomega/ghttp"
+)
+
+var _ = Describe("Docker credential helper", func() {
+	BeforeEach(func() {
+		login()
+	})
+
+	Describe("get", func() {
+		It("returns the registry credentials as json", func() {
+			responseJson := fmt.Sprintf(USER_CREDENTIAL_RESPONSE_JSON, "/docker-credentials/https_3A_2F_2Fregistry.example.com_3A5000", "my-user", "my-secret", "hash")
+
+			server.RouteToHandler("GET", "/api/v1/data",
+				CombineHandlers(
+					VerifyRequest("GET", "/api/v1/data", "name=%2Fdocker-credentials%2Fhttps_3A_2F_2Fregistry.example.com_3A5000&versions=1"),
+					RespondWith(http.StatusOK, `{"data":[`+responseJson+`]}`),
+				),
+			)
+
+			session := runCommandWithStdin(strings.NewReader("https://registry.example.com:5000\n"), "docker-credential", "get")
+
+			Eventually(session).Should(Exit(0))
+			Expect(session.Out.Contents()).To(MatchJSON(`{"ServerURL":"https://registry.example.com:5000","Username":"my-user","Secret":"my-secret"}`))
+		})
+
+		It("reports missing credentials the way docker expects", func() {
+			server.RouteToHandler("GET", "/api/v1/data",
+				RespondWith(http.StatusNotFound, `{"error":"The request could not be completed because the credential does not exist or you do not have sufficient authorization."}`),
+			)
+
+			session := runCommandWithStdin(strings.NewReader("registry.example.com"), "docker-credential", "get")
+
+			Eventually(session).Should(Exit(1))
+			Expect(session.Out).To(Say("credentials not found in native keychain"))
+		})
+
+		It("passes through other server errors", func() {
+			server.RouteToHandler("GET", "/api/v1/data",
+				RespondWith(http.StatusForbidden, `{"error":"You are not authorized to perform this action."}`),
+			)
+
+			session := runCommandWithStdin(strings.NewReader("registry.example.com"), "docker-credential", "get")
+
+			Eventually(session).Should(Exit(1))
+			Expect(session.Out.Contents()).NotTo(ContainSubstring("credentials not found"))
+			Expect(session.Err).To(Say("You are not authorized to perform this action."))
+		})
+
+		It("requires a server url", func() {
+			session := runCommandWithStdin(strings.NewReader(""), "docker-credential", "get")
+
+			Eventually(session).Should(Exit(1))
+			Expect(session.Err).To(Say("A server URL must be provided on stdin."))
+		})
+	})
+
+	Describe("store", func() {
+		It("sets a user credential under the configured path", func() {
+			responseJson := fmt.Sprintf(USER_CREDENTIAL_RESPONSE_JSON, "/registries/https_3A_2F_2Fregistry.example.com_2F", "my-user", "my-secret", "hash")
+
+			server.RouteToHandler("PUT", "/api/v1/data",
+				CombineHandlers(
+					VerifyJSON(`{"name":"/registries/https_3A_2F_2Fregistry.example.com_2F","type":"user","value":{"username":"my-user","password":"my-secret"},"overwrite":true}`),
+					RespondWith(http.StatusOK, responseJson),
+				),
+			)
+
+			input := `{"ServerURL":"https://registry.example.com/","Username":"my-user","Secret":"my-secret"}`
+			session := runCommandWithStdin(strings.NewReader(input), "docker-credential", "--path", "/registries", "store")
+
+			Eventually(session).Should(Exit(0))
+			Expect(server.ReceivedRequests()).To(HaveLen(1))
+		})
+	})
+
+	Describe("erase", func() {
+		It("deletes the registry credential", func() {
+			server.RouteToHandler("DELETE", "/api/v1/data",
+				CombineHandlers(
+					VerifyRequest("DELETE", "/api/v1/data", "name=%2Fdocker-credentials%2Fregistry.example.com"),
+					RespondWith(http.StatusOK, ""),
+				),
+			)
+
+			session := runCommandWithStdin(strings.NewReader("registry.example.com"), "docker-credential", "erase")
+
+			Eventually(session).Should(Exit(0))
+		})
+	})
+
+	Describe("list", func() {
+		It("maps server urls to usernames", func() {
+			responseJson := fmt.Sprintf(USER_CREDENTIAL_RESPONSE_JSON, "/docker-credentials/https_3A_2F_2Fregistry.example.com_3A5000", "my-user", "my-secret", "hash")
+
+			server.RouteToHandler("GET", "/api/v1/data",
+				func(w http.ResponseWriter, r *http.Request) {
+					if r.URL.Query().Get("path") != "" {
+						Expect(r.URL.Query().Get("path")).To(Equal("/docker-credentials"))
+						w.Write([]byte(`{"credentials":[{"name":"/docker-credentials/https_3A_2F_2Fregistry.example.com_3A5000","version_created_at":"` + TIMESTAMP + `"}]}`))
+						return
+					}
+					Expect(r.URL.Query().Get("name")).To(Equal("/docker-credentials/https_3A_2F_2Fregistry.example.com_3A5000"))
+					w.Write([]byte(`{"data":[` + responseJson + `]}`))
+				},
+			)
+
+			session := runCommand("docker-credential", "list")
+
+			Eventually(session).Should(Exit(0))
+			Expect(session.Out.Contents()).To(MatchJSON(`{"https://registry.example.com:5000":"my-user"}`))
+		})
+
+		It("reports credentials that cannot be retrieved", func() {
+			server.RouteToHandler("GET", "/api/v1/data",
+				func(w http.ResponseWriter, r *http.Request) {
+					if r.URL.Query().Get("path") != "" {
+						w.Write([]byte(`{"credentials":[{"name":"/docker-credentials/registry.example.com","version_created_at":"` + TIMESTAMP + `"}]}`))
+						return
+					}
+					w.WriteHeader(http.StatusInternalServerError)
+					w.Write([]byte(`{"error":"An application error occurred."}`))
+				},
+			)
+
+			session := runCommand("docker-credential", "list")
+
+			Eventually(session).Should(Exit(1))
+			Expect(session.Out.Contents()).To(BeEmpty())
+			Expect(session.Err).To(Say("An application error occurred."))
+		})
+
+		It("lists server urls exactly as they were stored", func() {
+			store := newCredentialStore(server, map[string]string{})
+
+			for _, serverURL := range []string{"https://registry.example.com:5000/", "registry_example.com", "http://localhost:5000"} {
+				input := `{"ServerURL":"` + serverURL + `","Username":"user-` + serverURL + `","Secret":"my-secret"}`
+				Eventually(runCommandWithStdin(strings.NewReader(input), "docker-credential", "store")).Should(Exit(0))
+			}
+
+			stored := map[string]string{}
+			for _, set := range store.sets {
+				name := set["name"].(string)
+				username := set["value"].(map[string]interface{})["username"].(string)
+				stored[name] = fmt.Sprintf(USER_CREDENTIAL_RESPONSE_JSON, name, username, "my-secret", "hash")
+			}
+			newCredentialStore(server, stored)
+
+			session := runCommand("docker-credential", "list")
+
+			Eventually(session).Should(Exit(0))
+			Expect(session.Out.Contents()).To(MatchJSON(`{
+				"https://registry.example.com:5000/":"user-https://registry.example.com:5000/",
+				"registry_example.com":"user-registry_example.com",
+				"http://localhost:5000":"user-http://localhost:5000"
+			}`))
+		})
+	})
+
+	It("rejects unknown actions", func() {
+		session := runCommand("docker-credential", "fetch")
+
+		Eventually(session).Should(Exit(1))
+		Expect(session.Err).To(Say("The action 'fetch' is not supported"))
+	})
+
+	It("rejects unknown actions before requiring authentication", func() {
+		authServer.RouteToHandler("DELETE", "/oauth/token/revoke/test-refresh-token",
+			RespondWith(http.StatusOK, nil),
+		)
+		runCommand("logout")
+
+		session := runCommand("docker-credential", "fetch")
+
+		Eventually(session).Should(Exit(1))
+		Expect(session.Err).To(Say("The action 'fetch' is not supported"))
+	})
+
+	It("runs as the helper when invoked as docker-credential-credhub", func() {
+		dir, err := ioutil.TempDir("", "docker-credential")
+		Expect(err).NotTo(HaveOccurred())
+		defer os.RemoveAll(dir)
+
+		helperPath := filepath.Join(dir, commands.DockerCredentialHelperName)
+		Expect(os.Symlink(commandPath, helperPath)).To(Succeed())
+
+		session, err := Start(exec.Command(helperPath, "fetch"), GinkgoWriter, GinkgoWriter)
+		Expect(err).NotTo(HaveOccurred())
+
+		Eventually(session).Should(Exit(1))
+		Expect(session.Err).To(Say("The action 'fetch' is not supported"))
+	})
+})
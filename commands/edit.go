@@ -0,0 +1,227 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+
+	"github.com/cloudfoundry-incubator/credhub-cli/config"
+	"github.com/cloudfoundry-incubator/credhub-cli/credhub"
+	"github.com/cloudfoundry-incubator/credhub-cli/credhub/credentials"
+	"github.com/cloudfoundry-incubator/credhub-cli/errors"
+	"github.com/cloudfoundry-incubator/credhub-cli/models"
+	"github.com/cloudfoundry-incubator/credhub-cli/util"
+	"gopkg.in/yaml.v2"
+)
+
+type EditCommand struct {
+	CredentialIdentifier string `short:"n" long:"name" required:"yes" description:"Name of the credential to edit"`
+	Json                 bool   `long:"json" description:"Edit structured values as JSON instead of YAML"`
+}
+
+// editableFields lists the fields that may be set for each structured
+// credential type. Values of json credentials may have any fields.
+var editableFields = map[string][]string{
+	"user":        {"username", "password"},
+	"certificate": {"ca", "ca_name", "certificate", "private_key"},
+	"ssh":         {"public_key", "private_key"},
+	"rsa":         {"public_key", "private_key"},
+}
+
+func (cmd EditCommand) Execute([]string) error {
+	cfg, err := config.ReadConfig()
+	if err != nil {
+		return err
+	}
+
+	credhubClient, err := initializeCredhubClient(cfg)
+	if err != nil {
+		return err
+	}
+
+	current, err := credhubClient.GetLatestVersion(cmd.CredentialIdentifier)
+	if err != nil {
+		return err
+	}
+
+	document, err := cmd.render(current)
+	if err != nil {
+		return err
+	}
+
+	edited, err := cmd.editInEditor(document)
+	if err != nil {
+		return err
+	}
+
+	value, err := cmd.parse(current.Type, edited)
+	if err != nil {
+		return err
+	}
+
+	original := current
+	original.Value = rollbackValue(current)
+	editedCredential := current
+	editedCredential.Value = value
+
+	hunks := util.UnifiedDiff(
+		diffDocument(map[string]credentials.Credential{current.Name: original}, current.Name, nil),
+		diffDocument(map[string]credentials.Credential{current.Name: editedCredential}, current.Name, nil),
+	)
+	if hunks == "" {
+		fmt.Println("No changes made.")
+		return nil
+	}
+
+	fmt.Printf("--- %s (version %s)\n+++ %s (edited)\n%s", current.Name, current.Id, current.Name, hunks)
+
+	credential, err := credhubClient.SetCredentialIfCurrent(current.Name, current.Type, value, current.Id)
+	if _, ok := err.(*credhub.VersionConflictError); ok {
+		return errors.NewCredentialChangedError(current.Name)
+	}
+	if err != nil {
+		return err
+	}
+
+	printCredential(CredHub.OutputJson, credential)
+
+	return nil
+}
+
+// render returns the document to edit. Values of string credentials are
+// edited as plain text.
+func (cmd EditCommand) render(credential credentials.Credential) ([]byte, error) {
+	value := rollbackValue(credential)
+
+	if s, ok := value.(string); ok {
+		return []byte(s + "\n"), nil
+	}
+
+	if cmd.Json {
+		document, err := json.MarshalIndent(value, "", "  ")
+		return append(document, '\n'), err
+	}
+
+	return yaml.Marshal(value)
+}
+
+func (cmd EditCommand) parse(credType string, document []byte) (interface{}, error) {
+	if credType == "value" || credType == "password" {
+		value := strings.TrimRight(string(document), "\r\n")
+		if value == "" {
+			return nil, errors.NewInvalidEditedValueError(credType, "the value is empty")
+		}
+		return value, nil
+	}
+
+	var value interface{}
+	if cmd.Json {
+		if err := json.Unmarshal(document, &value); err != nil {
+			return nil, errors.NewInvalidEditedValueError(credType, err.Error())
+		}
+	} else {
+		if err := yaml.Unmarshal(document, &value); err != nil {
+			return nil, errors.NewInvalidEditedValueError(credType, err.Error())
+		}
+		value = models.UnpackYAMLValue(value)
+	}
+
+	fields, ok := value.(map[string]interface{})
+	if !ok {
+		return nil, errors.NewInvalidEditedValueError(credType, "the value must be a map")
+	}
+
+	allowed, ok := editableFields[credType]
+	if !ok {
+		return fields, nil
+	}
+
+	for _, key := range sortedKeys(fields) {
+		if !containsString(allowed, key) {
+			return nil, errors.NewInvalidEditedValueError(credType, fmt.Sprintf("unexpected field '%s'", key))
+		}
+		if _, ok := fields[key].(string); !ok && fields[key] != nil {
+			return nil, errors.NewInvalidEditedValueError(credType, fmt.Sprintf("the field '%s' must be a string", key))
+		}
+	}
+
+	return fields, nil
+}
+
+// editInEditor writes document to a temporary file, opens it in the user's
+// editor and returns the saved contents.
+func (cmd EditCommand) editInEditor(document []byte) ([]byte, error) {
+	extension := ".yml"
+	if cmd.Json {
+		extension = ".json"
+	}
+
+	dir, err := ioutil.TempDir("", "credhub-edit")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "credential"+extension)
+
+	if err := ioutil.WriteFile(path, document, 0600); err != nil {
+		return nil, err
+	}
+
+	editor := editorCommand()
+	var editorCmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		editorCmd = exec.Command("cmd", "/C", editor+` "`+path+`"`)
+	} else {
+		editorCmd = exec.Command("sh", "-c", editor+` "$1"`, "sh", path)
+	}
+	editorCmd.Stdin = os.Stdin
+	editorCmd.Stdout = os.Stdout
+	editorCmd.Stderr = os.Stderr
+
+	if err := editorCmd.Run(); err != nil {
+		return nil, err
+	}
+
+	return ioutil.ReadFile(path)
+}
+
+func editorCommand() string {
+	for _, variable := range []string{"VISUAL", "EDITOR"} {
+		if editor := os.Getenv(variable); editor != "" {
+			return editor
+		}
+	}
+
+	if runtime.GOOS == "windows" {
+		return "notepad"
+	}
+
+	return "vi"
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+
+	return false
+}
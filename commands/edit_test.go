@@ -0,0 +1,137 @@
+package commands_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gbytes"
+	. "github.com/onsi/gomega/gexec"
+	. "github.com/onsi/gomega/ghttp"
+)
+
+var _ = Describe("Edit", func() {
+	var (
+		dir    string
+		edited string
+		sets   []map[string]interface{}
+	)
+
+	BeforeEach(func() {
+		login()
+
+		var err error
+		dir, err = ioutil.TempDir("", "credhub-edit-test")
+		Expect(err).NotTo(HaveOccurred())
+		edited = filepath.Join(dir, "edited")
+
+		sets = nil
+		server.RouteToHandler("PUT", "/api/v1/data", func(w http.ResponseWriter, r *http.Request) {
+			body, _ := ioutil.ReadAll(r.Body)
+			var request map[string]interface{}
+			Expect(json.Unmarshal(body, &request)).To(Succeed())
+			sets = append(sets, request)
+
+			value, _ := json.Marshal(request["value"])
+			w.Write([]byte(versionedCredential("edited-id", request["type"].(string), request["name"].(string), string(value))))
+		})
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(dir)
+	})
+
+	// editWith runs edit with an editor that replaces the file with contents.
+	editWith := func(contents string, args ...string) *Session {
+		Expect(ioutil.WriteFile(edited, []byte(contents), 0600)).To(Succeed())
+		return runCommandWithEnv([]string{"VISUAL=", "EDITOR=cp " + edited}, append([]string{"edit"}, args...)...)
+	}
+
+	ItRequiresAuthentication("edit", "-n", "test-credential")
+
+	It("sets the edited value of a json credential", func() {
+		server.RouteToHandler("GET", "/api/v1/data",
+			CombineHandlers(
+				VerifyRequest("GET", "/api/v1/data", "name=%2Fmy-json&versions=1"),
+				RespondWith(http.StatusOK, `{"data":[`+versionedCredential("current-id", "json", "/my-json", `{"host":"db.example.com","port":5432}`)+`]}`),
+			),
+		)
+
+		session := editWith("host: db.example.com\nport: 6432\n", "-n", "/my-json")
+
+		Eventually(session).Should(Exit(0))
+		Expect(session.Out).To(Say(`--- /my-json \(version current-id\)\n\+\+\+ /my-json \(edited\)\n`))
+		Expect(session.Out).To(Say(`-  port: 5432\n\+  port: 6432\n`))
+		Expect(session.Out).To(Say(`id: edited-id`))
+		Expect(sets).To(Equal([]map[string]interface{}{
+			{"name": "/my-json", "type": "json", "value": map[string]interface{}{"host": "db.example.com", "port": float64(6432)}, "overwrite": true},
+		}))
+	})
+
+	It("edits structured values as JSON", func() {
+		server.RouteToHandler("GET", "/api/v1/data",
+			RespondWith(http.StatusOK, `{"data":[`+versionedCredential("current-id", "user", "/my-user", `{"username":"admin","password":"old","password_hash":"hash"}`)+`]}`),
+		)
+
+		session := editWith(`{"username":"admin","password":"new"}`, "-n", "/my-user", "--json")
+
+		Eventually(session).Should(Exit(0))
+		Expect(sets).To(Equal([]map[string]interface{}{
+			{"name": "/my-user", "type": "user", "value": map[string]interface{}{"username": "admin", "password": "new"}, "overwrite": true},
+		}))
+	})
+
+	It("edits string values as plain text", func() {
+		server.RouteToHandler("GET", "/api/v1/data",
+			RespondWith(http.StatusOK, `{"data":[`+versionedCredential("current-id", "value", "/my-value", `"old"`)+`]}`),
+		)
+
+		session := editWith("new value\n", "-n", "/my-value")
+
+		Eventually(session).Should(Exit(0))
+		Expect(sets).To(Equal([]map[string]interface{}{
+			{"name": "/my-value", "type": "value", "value": "new value", "overwrite": true},
+		}))
+	})
+
+	It("does not set a new version when nothing changed", func() {
+		server.RouteToHandler("GET", "/api/v1/data",
+			RespondWith(http.StatusOK, `{"data":[`+versionedCredential("current-id", "value", "/my-value", `"same"`)+`]}`),
+		)
+
+		session := editWith("same\n", "-n", "/my-value")
+
+		Eventually(session).Should(Exit(0))
+		Expect(session.Out).To(Say("No changes made."))
+		Expect(sets).To(BeEmpty())
+	})
+
+	It("rejects fields that are not valid for the credential type", func() {
+		server.RouteToHandler("GET", "/api/v1/data",
+			RespondWith(http.StatusOK, `{"data":[`+versionedCredential("current-id", "user", "/my-user", `{"username":"admin","password":"old"}`)+`]}`),
+		)
+
+		session := editWith("username: admin\npasword: new\n", "-n", "/my-user")
+
+		Eventually(session).Should(Exit(2))
+		Expect(session.Err).To(Say("The edited value is not a valid user credential: unexpected field 'pasword'."))
+		Expect(sets).To(BeEmpty())
+	})
+
+	It("aborts when the credential changed on the server", func() {
+		server.AppendHandlers(
+			RespondWith(http.StatusOK, `{"data":[`+versionedCredential("current-id", "value", "/my-value", `"old"`)+`]}`),
+			RespondWith(http.StatusOK, `{"data":[`+versionedCredential("newer-id", "value", "/my-value", `"other"`)+`]}`),
+		)
+
+		session := editWith("new\n", "-n", "/my-value")
+
+		Eventually(session).Should(Exit(6))
+		Expect(session.Err).To(Say("The credential '/my-value' was changed on the server while it was being edited. No changes were made."))
+		Expect(sets).To(BeEmpty())
+	})
+})
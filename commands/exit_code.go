@@ -0,0 +1,82 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+
+	"github.com/cloudfoundry-incubator/credhub-cli/credhub"
+	"github.com/cloudfoundry-incubator/credhub-cli/errors"
+	"github.com/jessevdk/go-flags"
+)
+
+// ClassifyError returns the kind of a command failure and the HTTP status of the
+// server response that reported it, or 0 when the failure was not reported by
+// the server.
+func ClassifyError(err error) (errors.Kind, int) {
+	switch typed := err.(type) {
+	case *credhub.Error:
+		return statusKind(typed.StatusCode), typed.StatusCode
+	case *credhub.VersionConflictError:
+		return errors.VersionConflict, 0
+	case *flags.Error:
+		if typed.Type == flags.ErrHelp {
+			return errors.General, 0
+		}
+		return errors.Validation, 0
+	case *url.Error, net.Error:
+		return errors.Network, 0
+	}
+
+	return errors.KindOf(err), 0
+}
+
+func statusKind(status int) errors.Kind {
+	switch {
+	case status == http.StatusBadRequest || status == http.StatusUnprocessableEntity:
+		return errors.Validation
+	case status == http.StatusUnauthorized:
+		return errors.AuthRequired
+	case status == http.StatusForbidden:
+		return errors.Forbidden
+	case status == http.StatusNotFound:
+		return errors.NotFound
+	case status == http.StatusConflict || status == http.StatusPreconditionFailed:
+		return errors.VersionConflict
+	case status >= http.StatusInternalServerError:
+		return errors.ServerError
+	}
+
+	return errors.General
+}
+
+type errorOutput struct {
+	Error       string `json:"error"`
+	Description string `json:"description"`
+	Status      *int   `json:"status"`
+}
+
+// PrintError writes a command failure to w, as a JSON object with the kind,
+// message and HTTP status of the failure when outputJSON is set, and returns
+// the exit code for it.
+func PrintError(w io.Writer, err error, outputJSON bool) int {
+	kind, status := ClassifyError(err)
+
+	if !outputJSON {
+		fmt.Fprintln(w, err.Error())
+		return kind.ExitCode
+	}
+
+	output := errorOutput{Error: kind.Name, Description: err.Error()}
+	if status != 0 {
+		output.Status = &status
+	}
+
+	encoded, _ := json.MarshalIndent(output, "", "\t")
+	fmt.Fprintln(w, string(encoded))
+
+	return kind.ExitCode
+}
@@ -0,0 +1,146 @@
+package commands_test
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/cloudfoundry-incubator/credhub-cli/config"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gexec"
+	. "github.com/onsi/gomega/ghttp"
+)
+
+var _ = Describe("Exit codes", func() {
+	BeforeEach(func() {
+		login()
+	})
+
+	respondToGet := func(status int, body string) {
+		server.RouteToHandler("GET", "/api/v1/data", RespondWith(status, body))
+	}
+
+	It("exits with 5 when the credential does not exist", func() {
+		respondToGet(http.StatusNotFound, `{"error":"The request could not be completed because the credential does not exist or you do not have sufficient authorization."}`)
+
+		session := runCommand("get", "-n", "/missing")
+
+		Eventually(session).Should(Exit(5))
+		Expect(session.Err.Contents()).To(ContainSubstring("the credential does not exist"))
+	})
+
+	It("exits with 4 when the request is forbidden", func() {
+		respondToGet(http.StatusForbidden, `{"error":"You are not authorized to perform this action."}`)
+
+		session := runCommand("get", "-n", "/forbidden")
+
+		Eventually(session).Should(Exit(4))
+	})
+
+	It("exits with 8 when the server fails", func() {
+		respondToGet(http.StatusInternalServerError, `{"error":"An application error occurred."}`)
+
+		session := runCommand("get", "-n", "/broken")
+
+		Eventually(session).Should(Exit(8))
+	})
+
+	It("exits with 9 when some credentials could not be imported", func() {
+		server.RouteToHandler("PUT", "/api/v1/data", RespondWith(http.StatusBadRequest, `{"error":"The request does not include a valid type."}`))
+
+		session := runCommand("import", "-f", "../test/test_import_partial_fail_set.yml")
+
+		Eventually(session).Should(Exit(9))
+		Expect(session.Err.Contents()).To(ContainSubstring("3 of 3 credentials could not be imported."))
+	})
+
+	It("exits with 10 when the config file cannot be parsed", func() {
+		Expect(ioutil.WriteFile(config.ConfigPath(), []byte("{not-json"), 0600)).To(Succeed())
+
+		session := runCommand("get", "-n", "/my-password")
+
+		Eventually(session).Should(Exit(10))
+		Expect(session.Err.Contents()).To(ContainSubstring("could not be parsed"))
+	})
+
+	It("exits with 11 when the credential helper fails", func() {
+		Expect(config.WriteProfiles(config.Profiles{
+			CredentialHelper: "missing",
+			Profiles: map[string]config.Config{
+				config.DefaultProfile: {ApiURL: server.URL()},
+			},
+		})).To(Succeed())
+
+		session := runCommand("get", "-n", "/my-password")
+
+		Eventually(session).Should(Exit(11))
+		Expect(session.Err.Contents()).To(ContainSubstring("The credential helper 'credhub-credential-missing' failed to get tokens"))
+	})
+
+	Describe("with --output-json", func() {
+		It("writes server errors as JSON with their status", func() {
+			respondToGet(http.StatusNotFound, `{"error":"The request could not be completed because the credential does not exist or you do not have sufficient authorization."}`)
+
+			session := runCommand("get", "-n", "/missing", "--output-json")
+
+			Eventually(session).Should(Exit(5))
+			Expect(session.Err.Contents()).To(MatchJSON(`{
+				"error": "not_found",
+				"description": "The request could not be completed because the credential does not exist or you do not have sufficient authorization.",
+				"status": 404
+			}`))
+		})
+
+		It("writes version conflicts as JSON", func() {
+			server.AppendHandlers(
+				RespondWith(http.StatusOK, fmt.Sprintf(STRING_CREDENTIAL_ARRAY_RESPONSE_JSON, "password", "my-password", "old")),
+			)
+
+			session := runCommand("set", "-n", "my-password", "-t", "password", "-w", "potatoes", "--if-version", "stale-id", "--output-json")
+
+			Eventually(session).Should(Exit(6))
+			Expect(session.Err.Contents()).To(MatchJSON(`{
+				"error": "version_conflict",
+				"description": "The credential 'my-password' was not set because its current version is '` + UUID + `', not 'stale-id'. Get the current version and retry your request.",
+				"status": null
+			}`))
+		})
+
+		It("writes errors as JSON for commands that do not print credentials", func() {
+			server.RouteToHandler("DELETE", "/api/v1/data", RespondWith(http.StatusNotFound, `{"error":"The request could not be completed because the credential does not exist or you do not have sufficient authorization."}`))
+
+			session := runCommand("delete", "-n", "/missing", "--output-json")
+
+			Eventually(session).Should(Exit(5))
+			Expect(session.Err.Contents()).To(MatchJSON(`{
+				"error": "not_found",
+				"description": "The request could not be completed because the credential does not exist or you do not have sufficient authorization.",
+				"status": 404
+			}`))
+		})
+
+		It("accepts --output-json before the command", func() {
+			session := runCommand("--output-json", "import", "-f", "../test/test_import_incorrect_yaml.yml")
+
+			Eventually(session).Should(Exit(2))
+			Expect(session.Err.Contents()).To(MatchJSON(`{
+				"error": "validation",
+				"description": "The referenced file does not contain valid yaml structure. Please update and retry your request.",
+				"status": null
+			}`))
+		})
+
+		It("writes invalid arguments as JSON", func() {
+			session := runCommand("get", "--output-json")
+
+			Eventually(session).Should(Exit(2))
+			Expect(session.Err.Contents()).To(MatchJSON(`{
+				"error": "validation",
+				"description": "A name or ID must be provided. Please update and retry your request.",
+				"status": null
+			}`))
+		})
+	})
+})
@@ -10,14 +10,16 @@ type FindCommand struct {
 	PartialCredentialIdentifier string `short:"n" long:"name-like" description:"Find credentials whose name contains the query string"`
 	PathIdentifier              string `short:"p" long:"path" description:"Find credentials that exist under the provided path"`
 	AllPaths                    bool   `short:"a" long:"all-paths" description:"List all existing credential paths"`
-	OutputJson                  bool   `long:"output-json" description:"Return response in JSON format"`
 }
 
 func (cmd FindCommand) Execute([]string) error {
 	var output interface{}
 	var err error
 
-	cfg := config.ReadConfig()
+	cfg, err := config.ReadConfig()
+	if err != nil {
+		return err
+	}
 
 	credhubClient, err := initializeCredhubClient(cfg)
 	if err != nil {
@@ -46,7 +48,7 @@ func (cmd FindCommand) Execute([]string) error {
 		return err
 	}
 
-	printCredential(cmd.OutputJson, output)
+	printCredential(CredHub.OutputJson, output)
 
 	return nil
 }
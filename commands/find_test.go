@@ -20,7 +20,7 @@ var _ = Describe("Find", func() {
 
 	ItRequiresAuthentication("find", "-n", "test-credential")
 	ItRequiresAnAPIToBeSet("find", "-n", "test-credential")
-	ItAutomaticallyLogsIn("GET", "find_response.json", "/api/v1/data", "find")
+	ItAutomaticallyLogsIn("find", "find")
 
 	Describe("Help", func() {
 		ItBehavesLikeHelp("find", "f", func(session *Session) {
@@ -137,7 +137,7 @@ var _ = Describe("Find", func() {
 			session := runCommand("find", "-a", "--output-json")
 
 			Eventually(session.Err).Should(Say("No credentials exist which match the provided parameters."))
-			Eventually(session).Should(Exit(1))
+			Eventually(session).Should(Exit(5))
 		})
 	})
 
@@ -198,7 +198,7 @@ var _ = Describe("Find", func() {
 				})
 
 				It("exits with code 1", func() {
-					Eventually(session).Should(Exit(1))
+					Eventually(session).Should(Exit(5))
 				})
 			})
 		})
@@ -4,6 +4,7 @@ import (
 	"strings"
 
 	"github.com/cloudfoundry-incubator/credhub-cli/config"
+	"github.com/cloudfoundry-incubator/credhub-cli/credhub/credentials"
 	"github.com/cloudfoundry-incubator/credhub-cli/credhub/credentials/generate"
 	"github.com/cloudfoundry-incubator/credhub-cli/errors"
 	"github.com/cloudfoundry-incubator/credhub-cli/models"
@@ -13,7 +14,7 @@ type GenerateCommand struct {
 	CredentialIdentifier string   `short:"n" required:"yes" long:"name" description:"Name of the credential to generate"`
 	CredentialType       string   `short:"t" long:"type" description:"Sets the credential type to generate. Valid types include 'password', 'user', 'certificate', 'ssh' and 'rsa'."`
 	NoOverwrite          bool     `short:"O" long:"no-overwrite" description:"Credential is not modified if stored value already exists"`
-	OutputJson           bool     `long:"output-json" description:"Return response in JSON format"`
+	Mode                 string   `long:"mode" description:"How to handle an existing credential: 'overwrite' (default), 'no-overwrite' or 'converge'"`
 	Username             string   `short:"z" long:"username" description:"Sets the username value of the credential"`
 	Length               int      `short:"l" long:"length" description:"[Password, User] Length of the generated value (Default: 30)"`
 	IncludeSpecial       bool     `short:"S" long:"include-special" description:"[Password, User] Include special characters in the generated value"`
@@ -42,11 +43,19 @@ func (cmd GenerateCommand) Execute([]string) error {
 		return errors.NewGenerateEmptyTypeError()
 	}
 
+	mode, err := writeMode(cmd.Mode, cmd.NoOverwrite)
+	if err != nil {
+		return err
+	}
+
 	var parameters interface{}
 
 	cmd.CredentialType = strings.ToLower(cmd.CredentialType)
 
-	cfg := config.ReadConfig()
+	cfg, err := config.ReadConfig()
+	if err != nil {
+		return err
+	}
 
 	if len(cmd.Username) > 0 {
 		parameters = generate.User{
@@ -91,13 +100,18 @@ func (cmd GenerateCommand) Execute([]string) error {
 		return err
 	}
 
-	credential, err := credhubClient.GenerateCredential(cmd.CredentialIdentifier, cmd.CredentialType, parameters, !cmd.NoOverwrite)
+	var credential credentials.Credential
+	if mode != "" {
+		credential, err = credhubClient.GenerateCredentialWithMode(cmd.CredentialIdentifier, cmd.CredentialType, parameters, mode)
+	} else {
+		credential, err = credhubClient.GenerateCredential(cmd.CredentialIdentifier, cmd.CredentialType, parameters, !cmd.NoOverwrite)
+	}
 
 	if err != nil {
 		return err
 	}
 
-	printCredential(cmd.OutputJson, credential)
+	printCredential(CredHub.OutputJson, credential)
 
 	return nil
 }
@@ -22,11 +22,11 @@ var _ = Describe("Generate", func() {
 
 	ItRequiresAuthentication("generate", "-n", "test-credential", "-t", "password")
 	ItRequiresAnAPIToBeSet("generate", "-n", "test-credential", "-t", "password")
-	ItAutomaticallyLogsIn("POST", "generate_response.json", "/api/v1/data", "generate", "-n", "test-credential", "-t", "password")
+	ItAutomaticallyLogsIn("generate", "generate", "-n", "test-credential", "-t", "password")
 
 	It("requires a type", func() {
 		session := runCommand("generate", "-n", "my-credential")
-		Eventually(session).Should(Exit(1))
+		Eventually(session).Should(Exit(2))
 		Eventually(session.Err).Should(Say("A type must be specified when generating a credential. Valid types include 'password', 'user', 'certificate', 'ssh' and 'rsa'."))
 	})
 
@@ -110,6 +110,12 @@ var _ = Describe("Generate", func() {
 			Eventually(session).Should(Exit(0))
 		})
 
+		It("with a write mode", func() {
+			setupPasswordPostServer("my-password", "potatoes", `{"name":"my-password","type":"password","parameters":{},"mode":"converge"}`)
+			session := runCommand("generate", "-n", "my-password", "-t", "password", "--mode", "converge")
+			Eventually(session).Should(Exit(0))
+		})
+
 		It("including length", func() {
 			setupPasswordPostServer("my-password", "potatoes", generateRequestJson("password", "my-password", `{"length":42}`, true))
 			session := runCommand("generate", "-n", "my-password", "-t", "password", "-l", "42")
@@ -541,7 +547,7 @@ var _ = Describe("Generate", func() {
 		It("displays missing 'n' option as required parameters", func() {
 			session := runCommand("generate")
 
-			Eventually(session).Should(Exit(1))
+			Eventually(session).Should(Exit(2))
 
 			if runtime.GOOS == "windows" {
 				Expect(session.Err).To(Say("the required flag `/n, /name' was not specified"))
@@ -557,7 +563,7 @@ var _ = Describe("Generate", func() {
 
 			session := runCommand("generate", "-n", "my-value", "-t", "value")
 
-			Eventually(session).Should(Exit(1))
+			Eventually(session).Should(Exit(2))
 
 			Expect(session.Err).To(Say("test error"))
 		})
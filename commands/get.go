@@ -1,16 +1,29 @@
 package commands
 
 import (
+	"sync"
+
 	"github.com/cloudfoundry-incubator/credhub-cli/config"
+	"github.com/cloudfoundry-incubator/credhub-cli/credhub"
 	"github.com/cloudfoundry-incubator/credhub-cli/credhub/credentials"
 	"github.com/cloudfoundry-incubator/credhub-cli/errors"
 )
 
+// GET_WORKERS bounds the number of concurrent requests made when getting
+// several credentials at once.
+const GET_WORKERS = 10
+
 type GetCommand struct {
-	Name             string `short:"n" long:"name" description:"Name of the credential to retrieve"`
-	Id               string `long:"id" description:"ID of the credential to retrieve"`
-	NumberOfVersions int    `long:"versions" description:"Number of versions of the credential to retrieve"`
-	OutputJson       bool   `long:"output-json" description:"Return response in JSON format"`
+	Name             []string `short:"n" long:"name" description:"Name of the credential to retrieve (may be repeated)"`
+	Id               string   `long:"id" description:"ID of the credential to retrieve"`
+	Path             string   `short:"p" long:"path" description:"Path of the credentials to retrieve"`
+	Recursive        bool     `short:"r" long:"recursive" description:"Also retrieve credentials in paths below --path"`
+	NumberOfVersions int      `long:"versions" description:"Number of versions of the credential to retrieve"`
+}
+
+type bulkGetOutput struct {
+	Credentials map[string]interface{} `json:"credentials" yaml:"credentials"`
+	Errors      map[string]string      `json:"errors,omitempty" yaml:"errors,omitempty"`
 }
 
 func (cmd GetCommand) Execute([]string) error {
@@ -19,20 +32,27 @@ func (cmd GetCommand) Execute([]string) error {
 		err        error
 	)
 
-	cfg := config.ReadConfig()
+	cfg, err := config.ReadConfig()
+	if err != nil {
+		return err
+	}
 
 	credhubClient, err := initializeCredhubClient(cfg)
 	if err != nil {
 		return err
 	}
 
+	if cmd.Path != "" || len(cmd.Name) > 1 {
+		return cmd.getMany(credhubClient)
+	}
+
 	var arrayOfCredentials []credentials.Credential
 
-	if cmd.Name != "" {
+	if len(cmd.Name) == 1 {
 		if cmd.NumberOfVersions != 0 {
-			arrayOfCredentials, err = credhubClient.GetNVersions(cmd.Name, cmd.NumberOfVersions)
+			arrayOfCredentials, err = credhubClient.GetNVersions(cmd.Name[0], cmd.NumberOfVersions)
 		} else {
-			credential, err = credhubClient.GetLatestVersion(cmd.Name)
+			credential, err = credhubClient.GetLatestVersion(cmd.Name[0])
 		}
 	} else if cmd.Id != "" {
 		credential, err = credhubClient.GetById(cmd.Id)
@@ -48,10 +68,106 @@ func (cmd GetCommand) Execute([]string) error {
 		output := map[string][]credentials.Credential{
 			"versions": arrayOfCredentials,
 		}
-		printCredential(cmd.OutputJson, output)
+		printCredential(CredHub.OutputJson, output)
 	} else {
-		printCredential(cmd.OutputJson, credential)
+		printCredential(CredHub.OutputJson, credential)
 	}
 
 	return nil
 }
+
+// getMany prints the credentials named with --name or found under --path as
+// a single map keyed by name. Credentials that cannot be retrieved are listed
+// with their errors rather than stopping the others from being printed.
+func (cmd GetCommand) getMany(credhubClient *credhub.CredHub) error {
+	names := cmd.Name
+
+	if cmd.Path != "" {
+		found, err := namesUnderPath(credhubClient, cmd.Path, cmd.Recursive)
+		if err != nil {
+			return err
+		}
+		if len(found) == 0 && len(names) == 0 {
+			return errors.NewNoCredentialsFoundError(cmd.Path)
+		}
+		names = uniqueNames(append(names, found...))
+	}
+
+	type result struct {
+		name       string
+		credential interface{}
+		err        error
+	}
+
+	jobs := make(chan string)
+	results := make(chan result)
+
+	workers := GET_WORKERS
+	if len(names) < workers {
+		workers = len(names)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for name := range jobs {
+				credential, err := cmd.getOne(credhubClient, name)
+				results <- result{name, credential, err}
+			}
+		}()
+	}
+
+	go func() {
+		for _, name := range names {
+			jobs <- name
+		}
+		close(jobs)
+		wg.Wait()
+		close(results)
+	}()
+
+	output := bulkGetOutput{Credentials: map[string]interface{}{}}
+	for r := range results {
+		if r.err != nil {
+			if output.Errors == nil {
+				output.Errors = map[string]string{}
+			}
+			output.Errors[r.name] = r.err.Error()
+			continue
+		}
+		output.Credentials[r.name] = r.credential
+	}
+
+	printCredential(CredHub.OutputJson, output)
+
+	if len(output.Errors) > 0 {
+		return errors.NewGetFailuresError(len(output.Errors), len(output.Errors)+len(output.Credentials))
+	}
+
+	return nil
+}
+
+func (cmd GetCommand) getOne(credhubClient *credhub.CredHub, name string) (interface{}, error) {
+	if cmd.NumberOfVersions != 0 {
+		versions, err := credhubClient.GetNVersions(name, cmd.NumberOfVersions)
+		return map[string][]credentials.Credential{"versions": versions}, err
+	}
+
+	return credhubClient.GetLatestVersion(name)
+}
+
+func uniqueNames(names []string) []string {
+	seen := map[string]bool{}
+
+	var unique []string
+	for _, name := range names {
+		if !seen[name] {
+			seen[name] = true
+			unique = append(unique, name)
+		}
+	}
+
+	return unique
+}
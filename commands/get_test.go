@@ -21,7 +21,7 @@ var _ = Describe("Get", func() {
 
 	ItRequiresAuthentication("get", "-n", "test-credential")
 	ItRequiresAnAPIToBeSet("get", "-n", "test-credential")
-	ItAutomaticallyLogsIn("GET", "get_response.json", "/api/v1/data", "get", "-n", "test-credential")
+	ItAutomaticallyLogsIn("get", "get", "-n", "test-credential")
 
 	ItBehavesLikeHelp("get", "g", func(session *Session) {
 		Expect(session.Err).To(Say("Usage"))
@@ -35,7 +35,7 @@ var _ = Describe("Get", func() {
 	It("displays missing required parameter", func() {
 		session := runCommand("get")
 
-		Eventually(session).Should(Exit(1))
+		Eventually(session).Should(Exit(2))
 
 		if runtime.GOOS == "windows" {
 			Expect(session.Err).To(Say("A name or ID must be provided. Please update and retry your request."))
@@ -248,4 +248,57 @@ var _ = Describe("Get", func() {
 		Eventually(session).Should(Exit(0))
 		Eventually(session.Out).Should(Say("et''%/7\\(V&`|\\?m\\|Ckih\\$" + TIMESTAMP))
 	})
+
+	Describe("multiple credentials", func() {
+		BeforeEach(func() {
+			newCredentialStore(server, map[string]string{
+				"/deploy/app/password":    valueCredential("password", "/deploy/app/password", "app-password"),
+				"/deploy/app/db/password": valueCredential("password", "/deploy/app/db/password", "db-password"),
+				"/deploy/other":           valueCredential("value", "/deploy/other", "other-value"),
+			})
+		})
+
+		It("gets every name given", func() {
+			session := runCommand("get", "-n", "/deploy/app/password", "-n", "/deploy/other", "--output-json")
+
+			Eventually(session).Should(Exit(0))
+			Expect(session.Out.Contents()).To(MatchJSON(`{"credentials":{` +
+				`"/deploy/app/password":` + valueCredential("password", "/deploy/app/password", "app-password") + `,` +
+				`"/deploy/other":` + valueCredential("value", "/deploy/other", "other-value") + `}}`))
+		})
+
+		It("gets the credentials directly under a path", func() {
+			session := runCommand("get", "-p", "/deploy/app", "--output-json")
+
+			Eventually(session).Should(Exit(0))
+			Expect(session.Out.Contents()).To(MatchJSON(`{"credentials":{` +
+				`"/deploy/app/password":` + valueCredential("password", "/deploy/app/password", "app-password") + `}}`))
+		})
+
+		It("gets the credentials anywhere below a path when recursive", func() {
+			session := runCommand("get", "-p", "/deploy/app", "--recursive")
+
+			Eventually(session).Should(Exit(0))
+			Expect(session.Out).To(Say(`credentials:
+  /deploy/app/db/password:
+    id: ` + UUID + `
+    name: /deploy/app/db/password
+    version_created_at: ` + TIMESTAMP + `
+    type: password
+    value: db-password
+  /deploy/app/password:
+`))
+			Expect(session.Out.Contents()).NotTo(ContainSubstring("/deploy/other"))
+		})
+
+		It("lists the names that could not be retrieved", func() {
+			session := runCommand("get", "-n", "/deploy/other", "-n", "/deploy/missing", "--output-json")
+
+			Eventually(session).Should(Exit(9))
+			Expect(session.Out.Contents()).To(MatchJSON(`{` +
+				`"credentials":{"/deploy/other":` + valueCredential("value", "/deploy/other", "other-value") + `},` +
+				`"errors":{"/deploy/missing":"The request could not be completed because the credential does not exist or you do not have sufficient authorization."}}`))
+			Expect(session.Err).To(Say("1 of 2 credentials could not be retrieved."))
+		})
+	})
 })
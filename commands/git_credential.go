@@ -0,0 +1,150 @@
+package commands
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/cloudfoundry-incubator/credhub-cli/config"
+	"github.com/cloudfoundry-incubator/credhub-cli/credhub"
+	"github.com/cloudfoundry-incubator/credhub-cli/credhub/credentials/values"
+	"github.com/cloudfoundry-incubator/credhub-cli/errors"
+)
+
+// GitCredentialHelperName is the executable name git looks up for a
+// "credhub" credential.helper. When invoked under this name the CLI behaves as
+// `credhub git-credential`.
+const GitCredentialHelperName = "git-credential-credhub"
+
+type GitCredentialCommand struct {
+	Path string                  `long:"path" description:"Path under which repository credentials are stored" env:"CREDHUB_GIT_CREDENTIAL_PATH" default:"/git-credentials"`
+	Args GitCredentialActionArgs `positional-args:"yes" required:"yes"`
+}
+
+type GitCredentialActionArgs struct {
+	Action string `positional-arg-name:"ACTION" required:"yes" description:"One of get, store or erase"`
+}
+
+func (cmd GitCredentialCommand) Execute([]string) error {
+	switch cmd.Args.Action {
+	case "get", "store", "erase":
+	default:
+		// The protocol asks helpers to silently ignore operations they do not
+		// understand so that newer versions of git keep working.
+		return nil
+	}
+
+	attributes, err := readGitCredentialAttributes(os.Stdin)
+	if err != nil {
+		return err
+	}
+
+	name, err := gitCredentialName(cmd.Path, attributes)
+	if err != nil {
+		return err
+	}
+
+	cfg, err := config.ReadConfig()
+	if err != nil {
+		return err
+	}
+
+	credhubClient, err := initializeCredhubClient(cfg)
+	if err != nil {
+		return err
+	}
+
+	switch cmd.Args.Action {
+	case "get":
+		return gitCredentialGet(credhubClient, name, os.Stdout)
+	case "store":
+		return gitCredentialStore(credhubClient, name, attributes)
+	default:
+		return gitCredentialErase(credhubClient, name)
+	}
+}
+
+func gitCredentialGet(credhubClient *credhub.CredHub, name string, out io.Writer) error {
+	user, err := credhubClient.GetLatestUser(name)
+	if err != nil {
+		// Printing nothing tells git to fall back to the next helper or prompt.
+		if isNotFoundError(err) {
+			return nil
+		}
+		return err
+	}
+
+	if user.Value.Username != nil {
+		fmt.Fprintf(out, "username=%s\n", *user.Value.Username)
+	}
+	fmt.Fprintf(out, "password=%s\n", user.Value.Password)
+
+	return nil
+}
+
+func gitCredentialStore(credhubClient *credhub.CredHub, name string, attributes map[string]string) error {
+	for _, attribute := range []string{"username", "password"} {
+		if attributes[attribute] == "" {
+			return errors.NewMissingGitCredentialAttributeError(attribute)
+		}
+	}
+
+	username := attributes["username"]
+	value := values.User{
+		Username: &username,
+		Password: attributes["password"],
+	}
+
+	_, err := credhubClient.SetUser(name, value, true)
+	return err
+}
+
+func gitCredentialErase(credhubClient *credhub.CredHub, name string) error {
+	err := credhubClient.Delete(name)
+	if isNotFoundError(err) {
+		return nil
+	}
+	return err
+}
+
+// readGitCredentialAttributes reads key=value lines until a blank line or the
+// end of input, as described in git-credential(1).
+func readGitCredentialAttributes(in io.Reader) (map[string]string, error) {
+	attributes := map[string]string{}
+
+	scanner := bufio.NewScanner(in)
+	for scanner.Scan() {
+		line := strings.TrimSuffix(scanner.Text(), "\r")
+		if line == "" {
+			break
+		}
+
+		parts := strings.SplitN(line, "=", 2)
+		if len(parts) != 2 {
+			return nil, errors.NewInvalidGitCredentialAttributeError(line)
+		}
+		attributes[parts[0]] = parts[1]
+	}
+
+	return attributes, scanner.Err()
+}
+
+// gitCredentialName maps git's protocol, host and optional path attributes to
+// a credential name under path, e.g. protocol=https, host=example.com:8443 and
+// path=org/repo.git become <path>/https/example.com_8443/org/repo.git.
+func gitCredentialName(path string, attributes map[string]string) (string, error) {
+	for _, attribute := range []string{"protocol", "host"} {
+		if attributes[attribute] == "" {
+			return "", errors.NewMissingGitCredentialAttributeError(attribute)
+		}
+	}
+
+	name := strings.TrimRight(path, "/") + "/" + attributes["protocol"] + "/" + strings.Replace(attributes["host"], ":", "_", -1)
+	if repoPath := strings.Trim(attributes["path"], "/"); repoPath != "" {
+		name += "/" + repoPath
+	}
+
+	return name, nil
+}
@@ -0,0 +1,153 @@
+package commands_test
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/cloudfoundry-incubator/credhub-cli/commands"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gbytes"
+	. "github.com/onsi/gomega/gexec"
+	. "github.com/onsi/gomega/ghttp"
+)
+
+var _ = Describe("Git credential helper", func() {
+	BeforeEach(func() {
+		login()
+	})
+
+	Describe("get", func() {
+		It("prints the username and password for the repository", func() {
+			responseJson := fmt.Sprintf(USER_CREDENTIAL_RESPONSE_JSON, "/git-credentials/https/git.example.com_8443/org/repo.git", "my-user", "my-password", "hash")
+
+			server.RouteToHandler("GET", "/api/v1/data",
+				CombineHandlers(
+					VerifyRequest("GET", "/api/v1/data", "name=%2Fgit-credentials%2Fhttps%2Fgit.example.com_8443%2Forg%2Frepo.git&versions=1"),
+					RespondWith(http.StatusOK, `{"data":[`+responseJson+`]}`),
+				),
+			)
+
+			input := "protocol=https\nhost=git.example.com:8443\npath=org/repo.git\n\n"
+			session := runCommandWithStdin(strings.NewReader(input), "git-credential", "get")
+
+			Eventually(session).Should(Exit(0))
+			Expect(string(session.Out.Contents())).To(Equal("username=my-user\npassword=my-password\n"))
+		})
+
+		It("prints nothing when no credential exists", func() {
+			server.RouteToHandler("GET", "/api/v1/data",
+				RespondWith(http.StatusNotFound, `{"error":"The request could not be completed because the credential does not exist or you do not have sufficient authorization."}`),
+			)
+
+			session := runCommandWithStdin(strings.NewReader("protocol=https\nhost=git.example.com\n"), "git-credential", "get")
+
+			Eventually(session).Should(Exit(0))
+			Expect(session.Out.Contents()).To(BeEmpty())
+		})
+
+		It("passes through other server errors", func() {
+			server.RouteToHandler("GET", "/api/v1/data",
+				RespondWith(http.StatusForbidden, `{"error":"You are not authorized to perform this action."}`),
+			)
+
+			session := runCommandWithStdin(strings.NewReader("protocol=https\nhost=git.example.com\n"), "git-credential", "get")
+
+			Eventually(session).Should(Exit(1))
+			Expect(session.Out.Contents()).To(BeEmpty())
+			Expect(session.Err).To(Say("You are not authorized to perform this action."))
+		})
+
+		It("requires a host", func() {
+			session := runCommandWithStdin(strings.NewReader("protocol=https\n"), "git-credential", "get")
+
+			Eventually(session).Should(Exit(1))
+			Expect(session.Err).To(Say("The 'host' attribute must be provided on stdin."))
+		})
+	})
+
+	Describe("store", func() {
+		It("sets a user credential under the configured path", func() {
+			responseJson := fmt.Sprintf(USER_CREDENTIAL_RESPONSE_JSON, "/repos/https/git.example.com", "my-user", "my-password", "hash")
+
+			server.RouteToHandler("PUT", "/api/v1/data",
+				CombineHandlers(
+					VerifyJSON(`{"name":"/repos/https/git.example.com","type":"user","value":{"username":"my-user","password":"my-password"},"overwrite":true}`),
+					RespondWith(http.StatusOK, responseJson),
+				),
+			)
+
+			input := "protocol=https\nhost=git.example.com\nusername=my-user\npassword=my-password\n"
+			session := runCommandWithStdin(strings.NewReader(input), "git-credential", "--path", "/repos/", "store")
+
+			Eventually(session).Should(Exit(0))
+			Expect(server.ReceivedRequests()).To(HaveLen(1))
+		})
+	})
+
+	Describe("erase", func() {
+		It("deletes the repository credential", func() {
+			server.RouteToHandler("DELETE", "/api/v1/data",
+				CombineHandlers(
+					VerifyRequest("DELETE", "/api/v1/data", "name=%2Fgit-credentials%2Fhttps%2Fgit.example.com"),
+					RespondWith(http.StatusOK, ""),
+				),
+			)
+
+			session := runCommandWithStdin(strings.NewReader("protocol=https\nhost=git.example.com\n"), "git-credential", "erase")
+
+			Eventually(session).Should(Exit(0))
+		})
+
+		It("succeeds when the credential does not exist", func() {
+			server.RouteToHandler("DELETE", "/api/v1/data",
+				RespondWith(http.StatusNotFound, `{"error":"The request could not be completed because the credential does not exist or you do not have sufficient authorization."}`),
+			)
+
+			session := runCommandWithStdin(strings.NewReader("protocol=https\nhost=git.example.com\n"), "git-credential", "erase")
+
+			Eventually(session).Should(Exit(0))
+		})
+
+		It("reports other server errors", func() {
+			server.RouteToHandler("DELETE", "/api/v1/data",
+				RespondWith(http.StatusInternalServerError, `{"error":"An application error occurred."}`),
+			)
+
+			session := runCommandWithStdin(strings.NewReader("protocol=https\nhost=git.example.com\n"), "git-credential", "erase")
+
+			Eventually(session).Should(Exit(1))
+			Expect(session.Err).To(Say("An application error occurred."))
+		})
+	})
+
+	It("silently ignores unknown actions", func() {
+		session := runCommandWithStdin(strings.NewReader(""), "git-credential", "capability")
+
+		Eventually(session).Should(Exit(0))
+		Expect(session.Out.Contents()).To(BeEmpty())
+		Expect(server.ReceivedRequests()).To(BeEmpty())
+	})
+
+	It("runs as the helper when invoked as git-credential-credhub", func() {
+		dir, err := ioutil.TempDir("", "git-credential")
+		Expect(err).NotTo(HaveOccurred())
+		defer os.RemoveAll(dir)
+
+		helperPath := filepath.Join(dir, commands.GitCredentialHelperName)
+		Expect(os.Symlink(commandPath, helperPath)).To(Succeed())
+
+		cmd := exec.Command(helperPath, "get")
+		cmd.Stdin = strings.NewReader("protocol=https\n")
+		session, err := Start(cmd, GinkgoWriter, GinkgoWriter)
+		Expect(err).NotTo(HaveOccurred())
+
+		Eventually(session).Should(Exit(1))
+		Expect(session.Err).To(Say("The 'host' attribute must be provided on stdin."))
+	})
+})
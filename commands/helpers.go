@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"strings"
 
 	"net/http"
 
@@ -11,6 +12,8 @@ import (
 	"github.com/cloudfoundry-incubator/credhub-cli/config"
 	"github.com/cloudfoundry-incubator/credhub-cli/credhub"
 	"github.com/cloudfoundry-incubator/credhub-cli/credhub/auth"
+	"github.com/cloudfoundry-incubator/credhub-cli/credhub/credentials"
+	"github.com/cloudfoundry-incubator/credhub-cli/errors"
 	"gopkg.in/yaml.v2"
 )
 
@@ -36,6 +39,40 @@ func initializeCredhubClient(cfg config.Config) (*credhub.CredHub, error) {
 	return credhubClient, nil
 }
 
+// settableValue returns the value of credential in the form accepted when
+// setting it, without the fields the server derives from the value.
+func settableValue(credential credentials.Credential) interface{} {
+	value, ok := credential.Value.(map[string]interface{})
+	if !ok {
+		return credential.Value
+	}
+
+	settable := map[string]interface{}{}
+	for k, v := range value {
+		settable[k] = v
+	}
+
+	switch credential.Type {
+	case "user":
+		delete(settable, "password_hash")
+	case "ssh":
+		delete(settable, "public_key_fingerprint")
+	}
+
+	return settable
+}
+
+// profileCredhubClient returns a client for the named profile rather than the
+// active one.
+func profileCredhubClient(profile string) (*credhub.CredHub, error) {
+	cfg, err := config.ReadProfileConfig(profile)
+	if err != nil {
+		return nil, err
+	}
+
+	return initializeCredhubClient(cfg)
+}
+
 func printCredential(outputJson bool, v interface{}) {
 	if outputJson {
 		s, _ := json.MarshalIndent(v, "", "\t")
@@ -46,6 +83,13 @@ func printCredential(outputJson bool, v interface{}) {
 	}
 }
 
+// isNotFoundError reports whether err is the server's response for a
+// credential that does not exist.
+func isNotFoundError(err error) bool {
+	serverErr, ok := err.(*credhub.Error)
+	return ok && serverErr.StatusCode == http.StatusNotFound
+}
+
 func newCredhubClient(cfg *config.Config, clientId string, clientSecret string, usingClientCredentials bool) (*credhub.CredHub, error) {
 	credhubClient, err := credhub.New(cfg.ApiURL, credhub.CaCerts(cfg.CaCerts...), credhub.SkipTLSValidation(cfg.InsecureSkipVerify), credhub.Auth(auth.Uaa(
 		clientId,
@@ -57,10 +101,55 @@ func newCredhubClient(cfg *config.Config, clientId string, clientSecret string,
 		usingClientCredentials,
 	)),
 		credhub.AuthURL(cfg.AuthURL),
-		credhub.ServerVersion(cfg.ServerVersion))
+		credhub.ServerVersion(cfg.ServerVersion),
+		transportOptions())
 	return credhubClient, err
 }
 
+// transportOptions returns a client option applying the flags and environment
+// variables that control how requests are sent: --timeout, CREDHUB_PROXY,
+// CREDHUB_TRACE, CREDHUB_RECORD and CREDHUB_REPLAY.
+func transportOptions() credhub.Option {
+	return func(ch *credhub.CredHub) error {
+		options := []credhub.Option{
+			credhub.Timeout(client.Timeout()),
+			credhub.Dialer(client.ProxyDialer()),
+			credhub.Tracer(client.TraceWriter()),
+		}
+		if cassette, mode, ok := client.Cassette(); ok {
+			options = append(options, credhub.Recorder(cassette, mode))
+		}
+
+		for _, option := range options {
+			if err := option(ch); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// saveTokens writes the tokens held by the client's OAuth strategy back to the
+// config, so that long-running commands which refresh their token leave the
+// CLI logged in. Tokens obtained from client credentials in the environment
+// are not saved.
+func saveTokens(cfg config.Config, credhubClient *credhub.CredHub) error {
+	oauth, ok := credhubClient.Auth.(*auth.OAuthStrategy)
+	if !ok || clientCredentialsInEnvironment() {
+		return nil
+	}
+
+	if oauth.AccessToken() == cfg.AccessToken && oauth.RefreshToken() == cfg.RefreshToken {
+		return nil
+	}
+
+	return config.UpdateConfig(func(latest *config.Config) error {
+		latest.AccessToken = oauth.AccessToken()
+		latest.RefreshToken = oauth.RefreshToken()
+		return nil
+	})
+}
+
 func clientCredentialsInEnvironment() bool {
 	return os.Getenv("CREDHUB_CLIENT") != "" || os.Getenv("CREDHUB_SECRET") != ""
 }
@@ -77,3 +166,42 @@ func verifyAuthServerConnection(cfg config.Config, skipTlsValidation bool) error
 
 	return err
 }
+
+// namesUnderPath returns the names of the credentials directly under path, or
+// anywhere below it when recursive.
+func namesUnderPath(credhubClient *credhub.CredHub, path string, recursive bool) ([]string, error) {
+	results, err := credhubClient.FindByPath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	prefix := strings.TrimSuffix(path, "/") + "/"
+
+	var names []string
+	for _, result := range results.Credentials {
+		if !recursive && strings.Contains(strings.TrimPrefix(result.Name, prefix), "/") {
+			continue
+		}
+		names = append(names, result.Name)
+	}
+
+	return names, nil
+}
+
+// writeMode returns the mode selected with --mode, or an empty mode when
+// --no-overwrite should decide instead.
+func writeMode(mode string, noOverwrite bool) (credhub.Mode, error) {
+	if mode == "" {
+		return "", nil
+	}
+	if noOverwrite {
+		return "", errors.NewModeConflictError()
+	}
+
+	parsed, err := credhub.ParseMode(mode)
+	if err != nil {
+		return "", errors.NewInvalidModeError(mode)
+	}
+
+	return parsed, nil
+}
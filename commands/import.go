@@ -8,12 +8,15 @@ import (
 	"reflect"
 
 	"github.com/cloudfoundry-incubator/credhub-cli/config"
+	"github.com/cloudfoundry-incubator/credhub-cli/credhub"
+	"github.com/cloudfoundry-incubator/credhub-cli/credhub/credentials"
 	"github.com/cloudfoundry-incubator/credhub-cli/errors"
 	"github.com/cloudfoundry-incubator/credhub-cli/models"
 )
 
 type ImportCommand struct {
 	File string `short:"f" long:"file" description:"File containing credentials to import" required:"true"`
+	Mode string `long:"mode" description:"How to handle credentials that already exist: 'overwrite' (default), 'no-overwrite' or 'converge'"`
 }
 
 var (
@@ -22,26 +25,34 @@ var (
 )
 
 func (cmd ImportCommand) Execute([]string) error {
+	mode, err := writeMode(cmd.Mode, false)
+	if err != nil {
+		return err
+	}
+
 	err = bulkImport.ReadFile(cmd.File)
 
 	if err != nil {
 		return err
 	}
 
-	err := setCredentials(bulkImport)
+	err = setCredentials(bulkImport, mode)
 
 	return err
 }
 
-func setCredentials(bulkImport models.CredentialBulkImport) error {
+func setCredentials(bulkImport models.CredentialBulkImport, mode credhub.Mode) error {
 	var (
 		name       string
 		successful int
 		failed     int
 	)
-	errors := make([]string, 0)
+	failures := make([]string, 0)
 
-	cfg := config.ReadConfig()
+	cfg, err := config.ReadConfig()
+	if err != nil {
+		return err
+	}
 
 	credhubClient, err := initializeCredhubClient(cfg)
 	if err != nil {
@@ -56,7 +67,12 @@ func setCredentials(bulkImport models.CredentialBulkImport) error {
 			name = ""
 		}
 
-		result, err := credhubClient.SetCredential(name, credential["type"].(string), credential["value"], true)
+		var result credentials.Credential
+		if mode != "" {
+			result, err = credhubClient.SetCredentialWithMode(name, credential["type"].(string), credential["value"], mode)
+		} else {
+			result, err = credhubClient.SetCredential(name, credential["type"].(string), credential["value"], true)
+		}
 
 		if err != nil {
 			if isAuthenticationError(err) {
@@ -64,7 +80,7 @@ func setCredentials(bulkImport models.CredentialBulkImport) error {
 			}
 			failure := fmt.Sprintf("Credential '%s' at index %d could not be set: %v", name, i, err)
 			fmt.Println(failure + "\n")
-			errors = append(errors, " - "+failure)
+			failures = append(failures, " - "+failure)
 			failed++
 			continue
 		} else {
@@ -76,10 +92,14 @@ func setCredentials(bulkImport models.CredentialBulkImport) error {
 	fmt.Println("Import complete.")
 	fmt.Fprintf(os.Stdout, "Successfully set: %d\n", successful)
 	fmt.Fprintf(os.Stdout, "Failed to set: %d\n", failed)
-	for _, v := range errors {
+	for _, v := range failures {
 		fmt.Println(v)
 	}
 
+	if failed > 0 {
+		return errors.NewImportFailuresError(failed, successful+failed)
+	}
+
 	return nil
 }
 
@@ -1,6 +1,8 @@
 package commands_test
 
 import (
+	"encoding/json"
+	"fmt"
 	"net/http"
 
 	. "github.com/onsi/ginkgo"
@@ -72,6 +74,23 @@ Failed to set: 0
 		})
 	})
 
+	Describe("importing with a write mode", func() {
+		It("sends the mode with every credential", func() {
+			server.RouteToHandler("PUT", "/api/v1/data", func(w http.ResponseWriter, r *http.Request) {
+				var body map[string]interface{}
+				Expect(json.NewDecoder(r.Body).Decode(&body)).To(Succeed())
+				Expect(body["mode"]).To(Equal("no-overwrite"))
+				Expect(body).NotTo(HaveKey("overwrite"))
+				w.Write([]byte(fmt.Sprintf(STRING_CREDENTIAL_RESPONSE_JSON, "value", body["name"], "test-value")))
+			})
+
+			session := runCommand("import", "-f", "../test/test_import_file.yml", "--mode", "no-overwrite")
+
+			Eventually(session).Should(Exit(0))
+			Eventually(session.Out).Should(Say(`Successfully set: 7`))
+		})
+	})
+
 	Describe("when the yaml file starts with ---", func() {
 		It("sets all the credentials", func() {
 			setUpImportRequests()
@@ -27,7 +27,10 @@ func (cmd LoginCommand) Execute([]string) error {
 		refreshToken string
 		err          error
 	)
-	cfg := config.ReadConfig()
+	cfg, err := config.ReadOrNewConfig()
+	if err != nil {
+		return err
+	}
 
 	if cfg.ApiURL == "" && cmd.ServerUrl == "" {
 		return errors.NewNoApiUrlSetError()
@@ -78,14 +81,26 @@ func (cmd LoginCommand) Execute([]string) error {
 
 	if err != nil {
 		RevokeTokenIfNecessary(cfg)
-		MarkTokensAsRevokedInConfig(&cfg)
-		config.WriteConfig(cfg)
+		config.UpdateConfig(func(latest *config.Config) error {
+			if cmd.ServerUrl != "" {
+				copyTarget(latest, cfg)
+			}
+			MarkTokensAsRevokedInConfig(latest)
+			return nil
+		})
 		return errors.NewAuthorizationError()
 	}
 
 	cfg.AccessToken = accessToken
 	cfg.RefreshToken = refreshToken
-	config.WriteConfig(cfg)
+	config.UpdateConfig(func(latest *config.Config) error {
+		if cmd.ServerUrl != "" {
+			copyTarget(latest, cfg)
+		}
+		latest.AccessToken = accessToken
+		latest.RefreshToken = refreshToken
+		return nil
+	})
 
 	if cmd.ServerUrl != "" {
 		PrintWarnings(cmd.ServerUrl, cmd.SkipTlsValidation)
@@ -45,7 +45,7 @@ var _ = Describe("Login", func() {
 				session := runCommand("login", "--client-name", "test_client", "--username", "test-username")
 
 				Expect(uaaServer.ReceivedRequests()).Should(HaveLen(0))
-				Eventually(session).Should(Exit(1))
+				Eventually(session).Should(Exit(2))
 				Eventually(session.Err).Should(Say("Client and password credentials may not be combined. Please update and retry your request with a single login method."))
 			})
 		})
@@ -55,7 +55,7 @@ var _ = Describe("Login", func() {
 				session := runCommand("login", "--client-secret", "test_secret", "--username", "test-username")
 
 				Expect(uaaServer.ReceivedRequests()).Should(HaveLen(0))
-				Eventually(session).Should(Exit(1))
+				Eventually(session).Should(Exit(2))
 				Eventually(session.Err).Should(Say("Client and password credentials may not be combined. Please update and retry your request with a single login method."))
 			})
 		})
@@ -65,7 +65,7 @@ var _ = Describe("Login", func() {
 				session := runCommand("login", "--client-name", "test_client", "--password", "test-password")
 
 				Expect(uaaServer.ReceivedRequests()).Should(HaveLen(0))
-				Eventually(session).Should(Exit(1))
+				Eventually(session).Should(Exit(2))
 				Eventually(session.Err).Should(Say("Client and password credentials may not be combined. Please update and retry your request with a single login method."))
 			})
 		})
@@ -75,7 +75,7 @@ var _ = Describe("Login", func() {
 				session := runCommand("login", "--client-secret", "test_secret", "--password", "test-password")
 
 				Expect(uaaServer.ReceivedRequests()).Should(HaveLen(0))
-				Eventually(session).Should(Exit(1))
+				Eventually(session).Should(Exit(2))
 				Eventually(session.Err).Should(Say("Client and password credentials may not be combined. Please update and retry your request with a single login method."))
 			})
 		})
@@ -85,7 +85,7 @@ var _ = Describe("Login", func() {
 				session := runCommand("login", "--client-name", "test_client", "--client-secret", "test_secret", "--username", "test-username", "--password", "test-password")
 
 				Expect(uaaServer.ReceivedRequests()).Should(HaveLen(0))
-				Eventually(session).Should(Exit(1))
+				Eventually(session).Should(Exit(2))
 				Eventually(session.Err).Should(Say("Client and password credentials may not be combined. Please update and retry your request with a single login method."))
 			})
 		})
@@ -115,7 +115,7 @@ var _ = Describe("Login", func() {
 				Eventually(session).Should(Exit(0))
 				Eventually(session.Out).Should(Say("Login Successful"))
 				Eventually(session.Out.Contents()).ShouldNot(ContainSubstring("Setting the target url:"))
-				cfg := config.ReadConfig()
+				cfg, _ := config.ReadConfig()
 				Expect(cfg.AccessToken).To(Equal("2YotnFZFEjr1zCsicMWpAA"))
 			})
 		})
@@ -126,7 +126,7 @@ var _ = Describe("Login", func() {
 				Eventually(session.Out).Should(Say("password:"))
 				Eventually(session.Wait("10s").Out).Should(Say("Login Successful"))
 				Eventually(session).Should(Exit(0))
-				cfg := config.ReadConfig()
+				cfg, _ := config.ReadConfig()
 				Expect(cfg.AccessToken).To(Equal("2YotnFZFEjr1zCsicMWpAA"))
 			})
 		})
@@ -135,7 +135,7 @@ var _ = Describe("Login", func() {
 			It("fails authentication with an error message", func() {
 				session := runCommand("login", "-p", "pass")
 
-				Eventually(session).Should(Exit(1))
+				Eventually(session).Should(Exit(2))
 				Eventually(session.Err).Should(Say("The combination of parameters in the request is not allowed. Please validate your input and retry your request."))
 			})
 		})
@@ -178,7 +178,7 @@ var _ = Describe("Login", func() {
 				Eventually(session).Should(Exit(0))
 				Eventually(session.Out).Should(Say("Login Successful"))
 				Eventually(session.Out.Contents()).ShouldNot(ContainSubstring("Setting the target url:"))
-				cfg := config.ReadConfig()
+				cfg, _ := config.ReadConfig()
 				Expect(cfg.AccessToken).To(Equal("2YotnFZFEjr1zCsicMWpAA"))
 			})
 		})
@@ -191,7 +191,7 @@ var _ = Describe("Login", func() {
 				Eventually(session).Should(Exit(0))
 				Eventually(session.Out).Should(Say("Login Successful"))
 				Eventually(session.Out.Contents()).ShouldNot(ContainSubstring("Setting the target url:"))
-				cfg := config.ReadConfig()
+				cfg, _ := config.ReadConfig()
 				Expect(cfg.AccessToken).To(Equal("2YotnFZFEjr1zCsicMWpAA"))
 			})
 		})
@@ -204,7 +204,7 @@ var _ = Describe("Login", func() {
 				Eventually(session).Should(Exit(0))
 				Eventually(session.Out).Should(Say("Login Successful"))
 				Eventually(session.Out.Contents()).ShouldNot(ContainSubstring("Setting the target url:"))
-				cfg := config.ReadConfig()
+				cfg, _ := config.ReadConfig()
 				Expect(cfg.AccessToken).To(Equal("2YotnFZFEjr1zCsicMWpAA"))
 			})
 		})
@@ -217,7 +217,7 @@ var _ = Describe("Login", func() {
 				Eventually(session).Should(Exit(0))
 				Eventually(session.Out).Should(Say("Login Successful"))
 				Eventually(session.Out.Contents()).ShouldNot(ContainSubstring("Setting the target url:"))
-				cfg := config.ReadConfig()
+				cfg, _ := config.ReadConfig()
 				Expect(cfg.AccessToken).To(Equal("2YotnFZFEjr1zCsicMWpAA"))
 			})
 		})
@@ -227,7 +227,7 @@ var _ = Describe("Login", func() {
 				session := runCommand("login", "--client-name", "test_client")
 
 				Expect(uaaServer.ReceivedRequests()).Should(HaveLen(0))
-				Eventually(session).Should(Exit(1))
+				Eventually(session).Should(Exit(2))
 				Eventually(session.Err).Should(Say("Both client name and client secret must be provided to authenticate. Please update and retry your request."))
 			})
 		})
@@ -237,7 +237,7 @@ var _ = Describe("Login", func() {
 				session := runCommandWithEnv([]string{"CREDHUB_CLIENT=test_client"}, "login")
 
 				Expect(uaaServer.ReceivedRequests()).Should(HaveLen(0))
-				Eventually(session).Should(Exit(1))
+				Eventually(session).Should(Exit(2))
 				Eventually(session.Err).Should(Say("Both client name and client secret must be provided to authenticate. Please update and retry your request."))
 			})
 		})
@@ -247,7 +247,7 @@ var _ = Describe("Login", func() {
 				session := runCommand("login", "--client-secret", "test_secret")
 
 				Expect(uaaServer.ReceivedRequests()).Should(HaveLen(0))
-				Eventually(session).Should(Exit(1))
+				Eventually(session).Should(Exit(2))
 				Eventually(session.Err).Should(Say("Both client name and client secret must be provided to authenticate. Please update and retry your request."))
 			})
 		})
@@ -257,7 +257,7 @@ var _ = Describe("Login", func() {
 				session := runCommandWithEnv([]string{"CREDHUB_SECRET=test_secret"}, "login")
 
 				Expect(uaaServer.ReceivedRequests()).Should(HaveLen(0))
-				Eventually(session).Should(Exit(1))
+				Eventually(session).Should(Exit(2))
 				Eventually(session.Err).Should(Say("Both client name and client secret must be provided to authenticate. Please update and retry your request."))
 			})
 		})
@@ -300,7 +300,7 @@ var _ = Describe("Login", func() {
 			Expect(uaaServer.ReceivedRequests()).Should(HaveLen(2))
 			Eventually(session).Should(Exit(0))
 			Eventually(session.Out).Should(Say("Login Successful"))
-			cfg := config.ReadConfig()
+			cfg, _ := config.ReadConfig()
 			Expect(cfg.ApiURL).To(Equal(apiServer.URL()))
 			Expect(cfg.AuthURL).To(Equal(uaaServer.URL()))
 		})
@@ -325,7 +325,7 @@ var _ = Describe("Login", func() {
 			session := runCommand("login", "-u", "user", "-p", "pass", "-s", apiServer.URL(), "--ca-cert", "../test/server-tls-ca.pem")
 
 			Expect(session).Should(Exit(0))
-			cfg := config.ReadConfig()
+			cfg, _ := config.ReadConfig()
 
 			Expect(cfg.CaCerts).Should(Equal([]string{string(testCa)}))
 		})
@@ -353,7 +353,7 @@ var _ = Describe("Login", func() {
 			session := runCommandWithEnv([]string{"CREDHUB_CA_CERT=../test/server-tls-ca.pem"}, "login", "-s", server.URL(), "-u", "user", "-p", "pass")
 			Eventually(session).Should(Exit(0))
 
-			cfg := config.ReadConfig()
+			cfg, _ := config.ReadConfig()
 			Expect(cfg.CaCerts).To(ConsistOf([]string{string(serverCa)}))
 		})
 
@@ -377,12 +377,12 @@ var _ = Describe("Login", func() {
 				session := runCommand("login", "-s", apiServer.URL(), "-u", "user", "-p", "pass", "--skip-tls-validation")
 
 				Eventually(session).Should(Exit(0))
-				cfg := config.ReadConfig()
+				cfg, _ := config.ReadConfig()
 				Expect(cfg.InsecureSkipVerify).To(Equal(true))
 			})
 
 			It("resets skip-tls flag in the config file", func() {
-				cfg := config.ReadConfig()
+				cfg, _ := config.ReadConfig()
 				cfg.InsecureSkipVerify = true
 				err := config.WriteConfig(cfg)
 				Expect(err).NotTo(HaveOccurred())
@@ -390,7 +390,7 @@ var _ = Describe("Login", func() {
 				session := runCommand("login", "-s", apiServer.URL(), "-u", "user", "-p", "pass")
 
 				Eventually(session).Should(Exit(0))
-				cfg = config.ReadConfig()
+				cfg, _ = config.ReadConfig()
 				Expect(cfg.InsecureSkipVerify).To(Equal(false))
 			})
 
@@ -400,7 +400,7 @@ var _ = Describe("Login", func() {
 				setupServer(apiServer, uaaServer.URL())
 				session := runCommand("login", "-s", apiServer.URL(), "-u", "user", "-p", "pass")
 
-				Eventually(session).Should(Exit(1))
+				Eventually(session).Should(Exit(7))
 				Eventually(session.Err).Should(Say("Error connecting to the targeted API"))
 			})
 
@@ -415,7 +415,7 @@ var _ = Describe("Login", func() {
 
 			It("records skip-tls into config file even with http URLs (will do nothing with that value)", func() {
 				session := runCommand("login", "-s", apiServer.URL(), "-u", "user", "-p", "pass", "--skip-tls-validation")
-				cfg := config.ReadConfig()
+				cfg, _ := config.ReadConfig()
 
 				Eventually(session).Should(Exit(0))
 				Expect(cfg.InsecureSkipVerify).To(Equal(true))
@@ -425,30 +425,30 @@ var _ = Describe("Login", func() {
 		It("saves the oauth tokens", func() {
 			runCommand("login", "-u", "user", "-p", "pass", "-s", apiServer.URL())
 
-			cfg := config.ReadConfig()
+			cfg, _ := config.ReadConfig()
 			Expect(cfg.AccessToken).To(Equal("2YotnFZFEjr1zCsicMWpAA"))
 			Expect(cfg.RefreshToken).To(Equal("erousflkajqwer"))
 		})
 
 		It("returns an error if no cert is valid for CredHub", func() {
-			previousCfg := config.ReadConfig()
+			previousCfg, _ := config.ReadConfig()
 			session := runCommand("login", "-s", server.URL(), "u", "user", "-p", "pass", "--ca-cert", "../test/auth-tls-ca.pem")
 
-			Eventually(session).Should(Exit(1))
+			Eventually(session).Should(Exit(7))
 			Eventually(session.Err).Should(Say("certificate signed by unknown authority"))
 
-			cfg := config.ReadConfig()
+			cfg, _ := config.ReadConfig()
 			Expect(cfg.CaCerts).To(Equal(previousCfg.CaCerts))
 		})
 
 		It("returns an error if no cert is valid for the auth server", func() {
-			previousCfg := config.ReadConfig()
+			previousCfg, _ := config.ReadConfig()
 			session := runCommand("login", "-s", server.URL(), "-u", "user", "-p", "pass", "--ca-cert", "../test/server-tls-ca.pem")
 
-			Eventually(session).Should(Exit(1))
+			Eventually(session).Should(Exit(7))
 			Eventually(session.Err).Should(Say("certificate signed by unknown authority"))
 
-			cfg := config.ReadConfig()
+			cfg, _ := config.ReadConfig()
 			Expect(cfg.CaCerts).To(Equal(previousCfg.CaCerts))
 		})
 
@@ -470,7 +470,7 @@ var _ = Describe("Login", func() {
 
 			Eventually(session).Should(Exit(0))
 
-			cfg := config.ReadConfig()
+			cfg, _ := config.ReadConfig()
 			Expect(cfg.ApiURL).To(Equal(server.URL()))
 		})
 
@@ -487,22 +487,22 @@ var _ = Describe("Login", func() {
 			It("should not login", func() {
 				session := runCommand("login", "-u", "user", "-p", "pass", "-s", badServer.URL())
 
-				Eventually(session).Should(Exit(1))
+				Eventually(session).Should(Exit(7))
 				Eventually(session.Err).Should(Say("Error connecting to the targeted API"))
 				Expect(uaaServer.ReceivedRequests()).Should(HaveLen(0))
 			})
 
 			It("should not override config's existing API URL value", func() {
-				cfg := config.ReadConfig()
+				cfg, _ := config.ReadConfig()
 				cfg.ApiURL = "foo"
 				config.WriteConfig(cfg)
 
 				session := runCommand("login", "-u", "user", "-p", "pass", "-s", badServer.URL())
 
-				Eventually(session).Should(Exit(1))
+				Eventually(session).Should(Exit(7))
 				Eventually(session.Err).Should(Say("Error connecting to the targeted API"))
 				Expect(uaaServer.ReceivedRequests()).Should(HaveLen(0))
-				cfg2 := config.ReadConfig()
+				cfg2, _ := config.ReadConfig()
 				Expect(cfg2.ApiURL).To(Equal("foo"))
 			})
 		})
@@ -532,7 +532,7 @@ var _ = Describe("Login", func() {
 				apiServer = NewServer()
 				setupServer(apiServer, badUaaServer.URL())
 
-				cfg := config.ReadConfig()
+				cfg, _ := config.ReadConfig()
 				cfg.AuthURL = badUaaServer.URL()
 				cfg.AccessToken = VALID_ACCESS_TOKEN
 				config.WriteConfig(cfg)
@@ -540,15 +540,15 @@ var _ = Describe("Login", func() {
 
 			It("fails to login", func() {
 				session = runCommand("login", "-u", "user", "-p", "pass")
-				Eventually(session).Should(Exit(1))
+				Eventually(session).Should(Exit(3))
 				Eventually(session.Err).Should(Say("The provided username and password combination are incorrect. Please validate your input and retry your request."))
 				Expect(badUaaServer.ReceivedRequests()).Should(HaveLen(2))
 			})
 
 			It("revokes any existing tokens", func() {
 				session = runCommand("login", "-u", "user", "-p", "pass")
-				Eventually(session).Should(Exit(1))
-				cfg := config.ReadConfig()
+				Eventually(session).Should(Exit(3))
+				cfg, _ := config.ReadConfig()
 				Expect(cfg.AccessToken).To(Equal("revoked"))
 				Expect(cfg.RefreshToken).To(Equal("revoked"))
 				Expect(badUaaServer.ReceivedRequests()).Should(HaveLen(2))
@@ -556,7 +556,7 @@ var _ = Describe("Login", func() {
 
 			It("doesn't print 'Setting the target url' message with -s flag", func() {
 				session = runCommand("login", "-u", "user", "-p", "pass", "-s", apiServer.URL())
-				Eventually(session).Should(Exit(1))
+				Eventually(session).Should(Exit(3))
 				Expect(session.Out).NotTo(Say("Setting the target url: " + apiServer.URL()))
 			})
 		})
@@ -568,14 +568,14 @@ var _ = Describe("Login", func() {
 		)
 
 		BeforeEach(func() {
-			cfg := config.ReadConfig()
+			cfg, _ := config.ReadConfig()
 			apiUrl = cfg.ApiURL
 			cfg.ApiURL = ""
 			config.WriteConfig(cfg)
 		})
 
 		AfterEach(func() {
-			cfg := config.ReadConfig()
+			cfg, _ := config.ReadConfig()
 			cfg.ApiURL = apiUrl
 			config.WriteConfig(cfg)
 		})
@@ -584,7 +584,7 @@ var _ = Describe("Login", func() {
 			It("returns an error message", func() {
 				session := runCommand("login")
 
-				Eventually(session).Should(Exit(1))
+				Eventually(session).Should(Exit(3))
 				Eventually(session.Err).Should(Say("An API target is not set. Please target the location of your server with `credhub api --server api.example.com` to continue."))
 			})
 		})
@@ -593,7 +593,7 @@ var _ = Describe("Login", func() {
 			It("returns an error message", func() {
 				session := runCommand("login", "-u", "user", "-p", "pass")
 
-				Eventually(session).Should(Exit(1))
+				Eventually(session).Should(Exit(3))
 				Eventually(session.Err).Should(Say("An API target is not set. Please target the location of your server with `credhub api --server api.example.com` to continue."))
 			})
 		})
@@ -611,7 +611,7 @@ var _ = Describe("Login", func() {
 })
 
 func setConfigAuthUrl(authUrl string) {
-	cfg := config.ReadConfig()
+	cfg, _ := config.ReadConfig()
 	cfg.AuthURL = authUrl
 	config.WriteConfig(cfg)
 }
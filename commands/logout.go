@@ -12,10 +12,15 @@ type LogoutCommand struct {
 }
 
 func (cmd LogoutCommand) Execute([]string) error {
-	cfg := config.ReadConfig()
+	cfg, err := config.ReadConfig()
+	if err != nil {
+		return err
+	}
 	RevokeTokenIfNecessary(cfg)
-	MarkTokensAsRevokedInConfig(&cfg)
-	config.WriteConfig(cfg)
+	config.UpdateConfig(func(latest *config.Config) error {
+		MarkTokensAsRevokedInConfig(latest)
+		return nil
+	})
 	fmt.Println("Logout Successful")
 	return nil
 }
@@ -23,7 +28,7 @@ func (cmd LogoutCommand) Execute([]string) error {
 func RevokeTokenIfNecessary(cfg config.Config) {
 	uaaClient := uaa.Client{
 		AuthURL: cfg.AuthURL,
-		Client: client.NewHttpClient(cfg),
+		Client:  client.NewHttpClient(cfg),
 	}
 
 	err = uaaClient.RevokeToken(cfg.AccessToken)
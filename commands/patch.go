@@ -0,0 +1,88 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"reflect"
+
+	"github.com/cloudfoundry-incubator/credhub-cli/config"
+	"github.com/cloudfoundry-incubator/credhub-cli/credhub"
+	"github.com/cloudfoundry-incubator/credhub-cli/credhub/credentials/values"
+	"github.com/cloudfoundry-incubator/credhub-cli/errors"
+	"github.com/cloudfoundry-incubator/credhub-cli/util"
+)
+
+type PatchCommand struct {
+	CredentialIdentifier string `short:"n" long:"name" required:"yes" description:"Name of the json credential to patch"`
+	Merge                string `long:"merge" description:"JSON merge patch (RFC 7386) to apply"`
+	JsonPatch            string `long:"json-patch" description:"File containing JSON patch operations (RFC 6902) to apply"`
+}
+
+func (cmd PatchCommand) Execute([]string) error {
+	if (cmd.Merge == "") == (cmd.JsonPatch == "") {
+		return errors.NewMissingPatchParametersError()
+	}
+
+	cfg, err := config.ReadConfig()
+	if err != nil {
+		return err
+	}
+
+	credhubClient, err := initializeCredhubClient(cfg)
+	if err != nil {
+		return err
+	}
+
+	current, err := credhubClient.GetLatestVersion(cmd.CredentialIdentifier)
+	if err != nil {
+		return err
+	}
+	if current.Type != "json" {
+		return errors.NewPatchTypeError(current.Name, current.Type)
+	}
+
+	patched, err := cmd.apply(current.Value)
+	if err != nil {
+		return err
+	}
+
+	value, ok := patched.(map[string]interface{})
+	if !ok {
+		return errors.NewPatchResultTypeError()
+	}
+
+	if reflect.DeepEqual(value, current.Value) {
+		fmt.Println("No changes made.")
+		return nil
+	}
+
+	credential, err := credhubClient.SetCredentialIfCurrent(current.Name, current.Type, values.JSON(value), current.Id)
+	if conflict, ok := err.(*credhub.VersionConflictError); ok {
+		return errors.NewVersionConflictError(conflict.Name, conflict.ExpectedId, conflict.CurrentId)
+	}
+	if err != nil {
+		return err
+	}
+
+	printCredential(CredHub.OutputJson, credential)
+
+	return nil
+}
+
+func (cmd PatchCommand) apply(document interface{}) (interface{}, error) {
+	if cmd.JsonPatch != "" {
+		operations, err := ioutil.ReadFile(cmd.JsonPatch)
+		if err != nil {
+			return nil, errors.NewFileLoadError()
+		}
+		return util.ApplyJSONPatch(document, operations)
+	}
+
+	var patch interface{}
+	if err := json.Unmarshal([]byte(cmd.Merge), &patch); err != nil {
+		return nil, errors.NewInvalidMergePatchError(err.Error())
+	}
+
+	return util.MergePatch(document, patch), nil
+}
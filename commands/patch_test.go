@@ -0,0 +1,124 @@
+package commands_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"os"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gbytes"
+	. "github.com/onsi/gomega/gexec"
+	. "github.com/onsi/gomega/ghttp"
+)
+
+var _ = Describe("Patch", func() {
+	var sets []map[string]interface{}
+
+	BeforeEach(func() {
+		login()
+
+		sets = nil
+		server.RouteToHandler("GET", "/api/v1/data",
+			CombineHandlers(
+				VerifyRequest("GET", "/api/v1/data", "name=%2Fmy-json&versions=1"),
+				RespondWith(http.StatusOK, `{"data":[`+versionedCredential("current-id", "json", "/my-json", `{"host":"db.example.com","port":5432,"tags":["a"],"old":true}`)+`]}`),
+			),
+		)
+		server.RouteToHandler("PUT", "/api/v1/data", func(w http.ResponseWriter, r *http.Request) {
+			body, _ := ioutil.ReadAll(r.Body)
+			var request map[string]interface{}
+			Expect(json.Unmarshal(body, &request)).To(Succeed())
+			sets = append(sets, request)
+
+			value, _ := json.Marshal(request["value"])
+			w.Write([]byte(versionedCredential("patched-id", "json", request["name"].(string), string(value))))
+		})
+	})
+
+	ItRequiresAuthentication("patch", "-n", "/my-json", "--merge", "{}")
+
+	It("applies a merge patch", func() {
+		session := runCommand("patch", "-n", "/my-json", "--merge", `{"port":6432,"old":null}`)
+
+		Eventually(session).Should(Exit(0))
+		Expect(session.Out).To(Say("id: patched-id"))
+		Expect(sets).To(Equal([]map[string]interface{}{
+			{"name": "/my-json", "type": "json", "value": map[string]interface{}{"host": "db.example.com", "port": float64(6432), "tags": []interface{}{"a"}}, "overwrite": true},
+		}))
+	})
+
+	It("applies JSON patch operations from a file", func() {
+		file, err := ioutil.TempFile("", "ops.json")
+		Expect(err).NotTo(HaveOccurred())
+		defer os.Remove(file.Name())
+		file.WriteString(`[{"op":"test","path":"/port","value":5432},{"op":"add","path":"/tags/-","value":"b"},{"op":"remove","path":"/old"}]`)
+		file.Close()
+
+		session := runCommand("patch", "-n", "/my-json", "--json-patch", file.Name(), "--output-json")
+
+		Eventually(session).Should(Exit(0))
+		Expect(sets).To(Equal([]map[string]interface{}{
+			{"name": "/my-json", "type": "json", "value": map[string]interface{}{"host": "db.example.com", "port": float64(5432), "tags": []interface{}{"a", "b"}}, "overwrite": true},
+		}))
+	})
+
+	It("does not patch a credential that changed after it was read", func() {
+		reads := 0
+		server.RouteToHandler("GET", "/api/v1/data", func(w http.ResponseWriter, r *http.Request) {
+			reads++
+			id := "current-id"
+			if reads > 1 {
+				id = "newer-id"
+			}
+			w.Write([]byte(`{"data":[` + versionedCredential(id, "json", "/my-json", `{"host":"db.example.com"}`) + `]}`))
+		})
+
+		session := runCommand("patch", "-n", "/my-json", "--merge", `{"port":6432}`)
+
+		Eventually(session).Should(Exit(6))
+		Expect(session.Err).To(Say("The credential '/my-json' was not set because its current version is 'newer-id', not 'current-id'."))
+		Expect(sets).To(BeEmpty())
+	})
+
+	It("does not set a new version when the patch changes nothing", func() {
+		session := runCommand("patch", "-n", "/my-json", "--merge", `{"port":5432}`)
+
+		Eventually(session).Should(Exit(0))
+		Expect(session.Out).To(Say("No changes made."))
+		Expect(sets).To(BeEmpty())
+	})
+
+	It("does not set anything when an operation fails", func() {
+		file, err := ioutil.TempFile("", "ops.json")
+		Expect(err).NotTo(HaveOccurred())
+		defer os.Remove(file.Name())
+		file.WriteString(`[{"op":"remove","path":"/old"},{"op":"test","path":"/port","value":1}]`)
+		file.Close()
+
+		session := runCommand("patch", "-n", "/my-json", "--json-patch", file.Name())
+
+		Eventually(session).Should(Exit(2))
+		Expect(session.Err).To(Say("The JSON patch operation 1 \\('test' at '/port'\\) could not be applied: the value does not match."))
+		Expect(sets).To(BeEmpty())
+	})
+
+	It("only patches json credentials", func() {
+		server.RouteToHandler("GET", "/api/v1/data",
+			RespondWith(http.StatusOK, `{"data":[`+versionedCredential("current-id", "value", "/my-value", `"plain"`)+`]}`),
+		)
+
+		session := runCommand("patch", "-n", "/my-value", "--merge", `{"a":1}`)
+
+		Eventually(session).Should(Exit(2))
+		Expect(session.Err).To(Say("The credential '/my-value' has type 'value'. Only json credentials can be patched."))
+	})
+
+	It("requires exactly one kind of patch", func() {
+		session := runCommand("patch", "-n", "/my-json")
+
+		Eventually(session).Should(Exit(2))
+		Expect(session.Err).To(Say("Exactly one of --merge or --json-patch must be provided."))
+	})
+})
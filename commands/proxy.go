@@ -0,0 +1,240 @@
+package commands
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"os"
+	"os/signal"
+	"path"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"github.com/cloudfoundry-incubator/credhub-cli/config"
+	"github.com/cloudfoundry-incubator/credhub-cli/credhub"
+)
+
+type ProxyCommand struct {
+	Port         int      `long:"port" default:"8845" description:"Local port to listen on"`
+	AllowedPaths []string `long:"allow-path" description:"Only forward requests whose path, or the names of all credentials they address, start with this prefix. Can be specified multiple times."`
+	ReadOnly     bool     `long:"read-only" description:"Only forward GET and HEAD requests"`
+	Token        string   `long:"token" description:"Token that requests must send in the X-CredHub-Proxy-Token header. A random token is generated by default."`
+}
+
+// proxyTokenHeader carries the token of the proxy session, so that only the
+// clients it was given to can use the proxy.
+const proxyTokenHeader = "X-CredHub-Proxy-Token"
+
+type strategyTransport struct {
+	credhubClient *credhub.CredHub
+}
+
+func (t strategyTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	return t.credhubClient.Auth.Do(req)
+}
+
+func (cmd ProxyCommand) Execute([]string) error {
+	cfg, err := config.ReadConfig()
+	if err != nil {
+		return err
+	}
+
+	credhubClient, err := initializeCredhubClient(cfg)
+	if err != nil {
+		return err
+	}
+
+	target, err := url.Parse(cfg.ApiURL)
+	if err != nil {
+		return err
+	}
+
+	listener, err := net.Listen("tcp", net.JoinHostPort("127.0.0.1", strconv.Itoa(cmd.Port)))
+	if err != nil {
+		return err
+	}
+
+	token := cmd.Token
+	if token == "" {
+		token, err = generateProxyToken()
+		if err != nil {
+			return err
+		}
+	}
+
+	port := strconv.Itoa(listener.Addr().(*net.TCPAddr).Port)
+	server := &http.Server{Handler: cmd.handler(target, credhubClient, port, token)}
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(signals)
+
+	go func() {
+		<-signals
+		server.Close()
+	}()
+
+	fmt.Printf("Proxying http://%s to %s\n", listener.Addr(), cfg.ApiURL)
+	if cmd.Token == "" {
+		fmt.Printf("Send the header '%s: %s' with every request\n", proxyTokenHeader, token)
+	}
+
+	if err := server.Serve(listener); err != nil && err != http.ErrServerClosed {
+		return err
+	}
+
+	return saveTokens(cfg, credhubClient)
+}
+
+func generateProxyToken() (string, error) {
+	token := make([]byte, 32)
+	if _, err := rand.Read(token); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(token), nil
+}
+
+func (cmd ProxyCommand) handler(target *url.URL, credhubClient *credhub.CredHub, port, token string) http.Handler {
+	proxy := httputil.NewSingleHostReverseProxy(target)
+	proxy.Transport = strategyTransport{credhubClient}
+
+	director := proxy.Director
+	proxy.Director = func(req *http.Request) {
+		director(req)
+		req.Host = target.Host
+		// The strategy sends requests through an http.Client, which refuses
+		// server-side requests.
+		req.RequestURI = ""
+		req.Header.Del("Authorization")
+		req.Header.Del(proxyTokenHeader)
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		// Browsers send requests from web pages with an Origin header, or
+		// with a foreign Host when the page rebinds its DNS name to the
+		// loopback address.
+		if req.Host != "127.0.0.1:"+port && req.Host != "localhost:"+port {
+			writeProxyError(w, http.StatusForbidden, "The host "+req.Host+" is not allowed by the proxy.")
+			return
+		}
+		if req.Header.Get("Origin") != "" {
+			writeProxyError(w, http.StatusForbidden, "Requests with an Origin header are not allowed by the proxy.")
+			return
+		}
+		if subtle.ConstantTimeCompare([]byte(req.Header.Get(proxyTokenHeader)), []byte(token)) != 1 {
+			writeProxyError(w, http.StatusUnauthorized, "The request does not include a valid "+proxyTokenHeader+" header.")
+			return
+		}
+
+		if cmd.ReadOnly && req.Method != http.MethodGet && req.Method != http.MethodHead {
+			writeProxyError(w, http.StatusMethodNotAllowed, "The proxy is read-only. Only GET and HEAD requests are forwarded.")
+			return
+		}
+
+		// Forward the cleaned path so that dot segments cannot escape an
+		// allowed prefix.
+		req.URL.Path = path.Clean("/" + req.URL.Path)
+		req.URL.RawPath = ""
+
+		if message := cmd.rejection(req); message != "" {
+			writeProxyError(w, http.StatusForbidden, message)
+			return
+		}
+
+		proxy.ServeHTTP(w, req)
+	})
+}
+
+// credentialPaths are the endpoints that address credentials by name, in
+// the name, path or credential_name query parameters or body fields.
+var credentialPaths = map[string]bool{
+	"/api/v1/data":        true,
+	"/api/v1/regenerate":  true,
+	"/api/v1/permissions": true,
+}
+
+// rejection returns why the allow-list refuses req, or an empty string when
+// req may be forwarded. A request is allowed when its path starts with an
+// allowed prefix, or when it goes to a credential endpoint and every
+// credential name it addresses starts with one.
+func (cmd ProxyCommand) rejection(req *http.Request) string {
+	if len(cmd.AllowedPaths) == 0 || cmd.allowed(req.URL.Path) {
+		return ""
+	}
+
+	notAllowed := "The path " + req.URL.Path + " is not allowed by the proxy."
+	if !credentialPaths[req.URL.Path] {
+		return notAllowed
+	}
+
+	query := req.URL.Query()
+	if _, ok := query["name-like"]; ok {
+		return "Finding credentials by name-like is not allowed by the proxy when --allow-path is set."
+	}
+
+	names := append(append(query["name"], query["path"]...), query["credential_name"]...)
+
+	body, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		return "The request body could not be read by the proxy."
+	}
+	req.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+	if len(bytes.TrimSpace(body)) > 0 {
+		var fields map[string]interface{}
+		if err := json.Unmarshal(body, &fields); err != nil {
+			return "The request body could not be read by the proxy."
+		}
+		for _, key := range []string{"name", "credential_name"} {
+			if value, ok := fields[key]; ok {
+				name, ok := value.(string)
+				if !ok {
+					return "The request body could not be read by the proxy."
+				}
+				names = append(names, name)
+			}
+		}
+	}
+
+	// Without a name, the request would find or address credentials
+	// anywhere on the server.
+	if len(names) == 0 {
+		return notAllowed
+	}
+
+	for _, name := range names {
+		if !cmd.allowed(name) {
+			return "The credential " + path.Clean("/"+name) + " is not allowed by the proxy."
+		}
+	}
+
+	return ""
+}
+
+func (cmd ProxyCommand) allowed(requestPath string) bool {
+	requestPath = path.Clean("/" + requestPath)
+	for _, prefix := range cmd.AllowedPaths {
+		prefix = strings.TrimSuffix(path.Clean("/"+prefix), "/")
+		if requestPath == prefix || strings.HasPrefix(requestPath, prefix+"/") {
+			return true
+		}
+	}
+
+	return false
+}
+
+func writeProxyError(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]string{"error": message})
+}
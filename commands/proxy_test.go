@@ -0,0 +1,291 @@
+package commands_test
+
+import (
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/cloudfoundry-incubator/credhub-cli/config"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gbytes"
+	. "github.com/onsi/gomega/gexec"
+	. "github.com/onsi/gomega/ghttp"
+)
+
+var _ = Describe("Proxy", func() {
+	var (
+		session  *Session
+		proxyURL string
+		token    string
+	)
+
+	BeforeEach(func() {
+		login()
+	})
+
+	startProxy := func(args ...string) {
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		Expect(err).NotTo(HaveOccurred())
+		port := strconv.Itoa(listener.Addr().(*net.TCPAddr).Port)
+		listener.Close()
+
+		proxyURL = "http://127.0.0.1:" + port
+
+		session, err = Start(exec.Command(commandPath, append([]string{"proxy", "--port", port}, args...)...), GinkgoWriter, GinkgoWriter)
+		Expect(err).NotTo(HaveOccurred())
+		Eventually(session.Out).Should(Say("Proxying " + proxyURL + " to " + server.URL()))
+		Eventually(session.Out).Should(Say("Send the header 'X-CredHub-Proxy-Token: [0-9a-f]{64}' with every request"))
+		token = regexp.MustCompile("X-CredHub-Proxy-Token: ([0-9a-f]+)").FindStringSubmatch(string(session.Out.Contents()))[1]
+	}
+
+	proxyRequest := func(method, path string, body io.Reader) *http.Response {
+		request, err := http.NewRequest(method, proxyURL+path, body)
+		Expect(err).NotTo(HaveOccurred())
+		request.Header.Set("X-CredHub-Proxy-Token", token)
+
+		response, err := http.DefaultClient.Do(request)
+		Expect(err).NotTo(HaveOccurred())
+
+		return response
+	}
+
+	AfterEach(func() {
+		if session != nil {
+			session.Interrupt()
+			Eventually(session).Should(Exit(0))
+		}
+		session = nil
+	})
+
+	ItRequiresAuthentication("proxy", "--port", "0")
+
+	It("forwards requests with the current bearer token", func() {
+		server.RouteToHandler("GET", "/api/v1/data",
+			CombineHandlers(
+				VerifyRequest("GET", "/api/v1/data", "name=my-value"),
+				VerifyHeaderKV("Authorization", "Bearer test-access-token"),
+				RespondWith(http.StatusOK, `{"data":[]}`),
+			),
+		)
+
+		startProxy()
+
+		request, _ := http.NewRequest("GET", proxyURL+"/api/v1/data?name=my-value", nil)
+		request.Header.Set("Authorization", "Bearer some-other-token")
+		request.Header.Set("X-CredHub-Proxy-Token", token)
+		response, err := http.DefaultClient.Do(request)
+		Expect(err).NotTo(HaveOccurred())
+		defer response.Body.Close()
+
+		Expect(response.StatusCode).To(Equal(http.StatusOK))
+		body, _ := ioutil.ReadAll(response.Body)
+		Expect(body).To(MatchJSON(`{"data":[]}`))
+	})
+
+	It("refreshes an expired token and saves it on exit", func() {
+		server.AppendHandlers(
+			CombineHandlers(
+				VerifyHeaderKV("Authorization", "Bearer test-access-token"),
+				RespondWith(http.StatusUnauthorized, `{"error":"access_token_expired","error_description":"Access token expired"}`),
+			),
+			CombineHandlers(
+				VerifyHeaderKV("Authorization", "Bearer new-access-token"),
+				RespondWith(http.StatusOK, `{"data":[]}`),
+			),
+		)
+		authServer.RouteToHandler("POST", "/oauth/token",
+			RespondWith(http.StatusOK, `{"access_token":"new-access-token","refresh_token":"new-refresh-token","token_type":"password","expires_in":123456789}`),
+		)
+
+		startProxy()
+
+		response := proxyRequest("GET", "/api/v1/data?name=my-value", nil)
+		response.Body.Close()
+		Expect(response.StatusCode).To(Equal(http.StatusOK))
+
+		session.Interrupt()
+		Eventually(session).Should(Exit(0))
+		session = nil
+
+		cfg, _ := config.ReadConfig()
+		Expect(cfg.AccessToken).To(Equal("new-access-token"))
+		Expect(cfg.RefreshToken).To(Equal("new-refresh-token"))
+	})
+
+	It("rejects writes in read-only mode", func() {
+		startProxy("--read-only")
+
+		response := proxyRequest("PUT", "/api/v1/data", strings.NewReader(`{"name":"foo","type":"value","value":"bar"}`))
+		defer response.Body.Close()
+
+		Expect(response.StatusCode).To(Equal(http.StatusMethodNotAllowed))
+		body, _ := ioutil.ReadAll(response.Body)
+		Expect(body).To(MatchJSON(`{"error":"The proxy is read-only. Only GET and HEAD requests are forwarded."}`))
+		Expect(server.ReceivedRequests()).To(BeEmpty())
+	})
+
+	It("only forwards allowed path prefixes", func() {
+		server.RouteToHandler("GET", "/api/v1/data", RespondWith(http.StatusOK, `{"data":[]}`))
+
+		startProxy("--allow-path", "/api/v1/data", "--allow-path", "/info")
+
+		response := proxyRequest("GET", "/api/v1/data?name=my-value", nil)
+		response.Body.Close()
+		Expect(response.StatusCode).To(Equal(http.StatusOK))
+
+		response = proxyRequest("GET", "/api/v1/data/../permissions?credential_name=foo", nil)
+		defer response.Body.Close()
+		Expect(response.StatusCode).To(Equal(http.StatusForbidden))
+		body, _ := ioutil.ReadAll(response.Body)
+		Expect(body).To(MatchJSON(`{"error":"The credential /foo is not allowed by the proxy."}`))
+	})
+
+	It("does not forward paths that only look like an allowed prefix", func() {
+		startProxy("--allow-path", "/api/v1/data")
+
+		response := proxyRequest("GET", "/api/v1/data-anything", nil)
+		defer response.Body.Close()
+
+		Expect(response.StatusCode).To(Equal(http.StatusForbidden))
+		body, _ := ioutil.ReadAll(response.Body)
+		Expect(body).To(MatchJSON(`{"error":"The path /api/v1/data-anything is not allowed by the proxy."}`))
+		Expect(server.ReceivedRequests()).To(BeEmpty())
+	})
+
+	It("does not forward dot segments out of an allowed prefix", func() {
+		startProxy("--allow-path", "/api/v1/data/")
+
+		response := proxyRequest("GET", "/api/v1/data/../../../info", nil)
+		defer response.Body.Close()
+
+		Expect(response.StatusCode).To(Equal(http.StatusForbidden))
+		body, _ := ioutil.ReadAll(response.Body)
+		Expect(body).To(MatchJSON(`{"error":"The path /info is not allowed by the proxy."}`))
+		Expect(server.ReceivedRequests()).To(BeEmpty())
+	})
+
+	Context("when --allow-path names a credential path", func() {
+		BeforeEach(func() {
+			server.RouteToHandler("GET", "/api/v1/data", RespondWith(http.StatusOK, `{"data":[]}`))
+			server.RouteToHandler("PUT", "/api/v1/data", RespondWith(http.StatusOK, `{}`))
+		})
+
+		It("forwards requests for credentials under the prefix", func() {
+			startProxy("--allow-path", "/team-a")
+
+			response := proxyRequest("GET", "/api/v1/data?name=/team-a/my-value", nil)
+			response.Body.Close()
+			Expect(response.StatusCode).To(Equal(http.StatusOK))
+
+			response = proxyRequest("GET", "/api/v1/data?path=/team-a/", nil)
+			response.Body.Close()
+			Expect(response.StatusCode).To(Equal(http.StatusOK))
+
+			response = proxyRequest("PUT", "/api/v1/data", strings.NewReader(`{"name":"team-a/my-value","type":"value","value":"bar"}`))
+			response.Body.Close()
+			Expect(response.StatusCode).To(Equal(http.StatusOK))
+
+			Expect(server.ReceivedRequests()).To(HaveLen(3))
+			Expect(server.ReceivedRequests()[2].ContentLength).To(BeNumerically(">", 0))
+		})
+
+		It("does not forward requests for credentials outside the prefix", func() {
+			startProxy("--allow-path", "/team-a")
+
+			for _, request := range []struct{ method, path, body, message string }{
+				{"GET", "/api/v1/data?name=/team-b/my-value", "", "The credential /team-b/my-value is not allowed by the proxy."},
+				{"GET", "/api/v1/data?name=/team-a/my-value&name=/team-b/my-value", "", "The credential /team-b/my-value is not allowed by the proxy."},
+				{"GET", "/api/v1/data?name=team-a/../team-b/my-value", "", "The credential /team-b/my-value is not allowed by the proxy."},
+				{"GET", "/api/v1/data?path=/", "", "The credential / is not allowed by the proxy."},
+				{"GET", "/api/v1/data?name-like=my-value", "", "Finding credentials by name-like is not allowed by the proxy when --allow-path is set."},
+				{"GET", "/api/v1/data?paths=true", "", "The path /api/v1/data is not allowed by the proxy."},
+				{"GET", "/api/v1/data/" + UUID, "", "The path /api/v1/data/" + UUID + " is not allowed by the proxy."},
+				{"GET", "/api/v1/permissions?credential_name=/team-b/my-value", "", "The credential /team-b/my-value is not allowed by the proxy."},
+				{"PUT", "/api/v1/data", `{"name":"/team-b/my-value","type":"value","value":"bar"}`, "The credential /team-b/my-value is not allowed by the proxy."},
+				{"PUT", "/api/v1/data?name=/team-a/my-value", `{"name":"/team-b/my-value","type":"value","value":"bar"}`, "The credential /team-b/my-value is not allowed by the proxy."},
+				{"POST", "/api/v1/regenerate", `{"name":"/team-b/my-value"}`, "The credential /team-b/my-value is not allowed by the proxy."},
+				{"PUT", "/api/v1/data", `[{"name":"/team-a/my-value"}]`, "The request body could not be read by the proxy."},
+			} {
+				response := proxyRequest(request.method, request.path, strings.NewReader(request.body))
+				body, _ := ioutil.ReadAll(response.Body)
+				response.Body.Close()
+
+				Expect(response.StatusCode).To(Equal(http.StatusForbidden), request.path)
+				Expect(body).To(MatchJSON(`{"error":"`+request.message+`"}`), request.path)
+			}
+
+			Expect(server.ReceivedRequests()).To(BeEmpty())
+		})
+	})
+
+	It("rejects requests without the session token", func() {
+		startProxy()
+
+		response, err := http.Get(proxyURL + "/api/v1/data?name=my-value")
+		Expect(err).NotTo(HaveOccurred())
+		defer response.Body.Close()
+
+		Expect(response.StatusCode).To(Equal(http.StatusUnauthorized))
+		body, _ := ioutil.ReadAll(response.Body)
+		Expect(body).To(MatchJSON(`{"error":"The request does not include a valid X-CredHub-Proxy-Token header."}`))
+		Expect(server.ReceivedRequests()).To(BeEmpty())
+	})
+
+	It("accepts the token given with --token", func() {
+		server.RouteToHandler("GET", "/api/v1/data", RespondWith(http.StatusOK, `{"data":[]}`))
+
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		Expect(err).NotTo(HaveOccurred())
+		port := strconv.Itoa(listener.Addr().(*net.TCPAddr).Port)
+		listener.Close()
+
+		session, err = Start(exec.Command(commandPath, "proxy", "--port", port, "--token", "my-token"), GinkgoWriter, GinkgoWriter)
+		Expect(err).NotTo(HaveOccurred())
+		Eventually(session.Out).Should(Say("Proxying http://127.0.0.1:" + port))
+		Expect(session.Out.Contents()).NotTo(ContainSubstring("my-token"))
+
+		request, _ := http.NewRequest("GET", "http://127.0.0.1:"+port+"/api/v1/data?name=my-value", nil)
+		request.Header.Set("X-CredHub-Proxy-Token", "my-token")
+		response, err := http.DefaultClient.Do(request)
+		Expect(err).NotTo(HaveOccurred())
+		response.Body.Close()
+
+		Expect(response.StatusCode).To(Equal(http.StatusOK))
+	})
+
+	It("rejects requests for another host", func() {
+		startProxy()
+
+		request, _ := http.NewRequest("GET", proxyURL+"/api/v1/data?name=my-value", nil)
+		request.Host = "attacker.example.com"
+		request.Header.Set("X-CredHub-Proxy-Token", token)
+		response, err := http.DefaultClient.Do(request)
+		Expect(err).NotTo(HaveOccurred())
+		defer response.Body.Close()
+
+		Expect(response.StatusCode).To(Equal(http.StatusForbidden))
+		body, _ := ioutil.ReadAll(response.Body)
+		Expect(body).To(MatchJSON(`{"error":"The host attacker.example.com is not allowed by the proxy."}`))
+		Expect(server.ReceivedRequests()).To(BeEmpty())
+	})
+
+	It("rejects requests with an Origin header", func() {
+		startProxy()
+
+		request, _ := http.NewRequest("GET", proxyURL+"/api/v1/data?name=my-value", nil)
+		request.Header.Set("Origin", "https://attacker.example.com")
+		request.Header.Set("X-CredHub-Proxy-Token", token)
+		response, err := http.DefaultClient.Do(request)
+		Expect(err).NotTo(HaveOccurred())
+		defer response.Body.Close()
+
+		Expect(response.StatusCode).To(Equal(http.StatusForbidden))
+		Expect(server.ReceivedRequests()).To(BeEmpty())
+	})
+})
@@ -0,0 +1,70 @@
+package commands_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gbytes"
+	. "github.com/onsi/gomega/gexec"
+)
+
+var _ = Describe("Recording", func() {
+	var (
+		dir      string
+		cassette string
+	)
+
+	BeforeEach(func() {
+		login()
+
+		newCredentialStore(server, map[string]string{
+			"/my-password": valueCredential("password", "/my-password", "hunter2"),
+		})
+
+		var err error
+		dir, err = ioutil.TempDir("", "credhub-cassette")
+		Expect(err).NotTo(HaveOccurred())
+		cassette = filepath.Join(dir, "cassette.json")
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(dir)
+	})
+
+	It("records requests to the file named by CREDHUB_RECORD without their secrets", func() {
+		session := runCommandWithEnv([]string{"CREDHUB_RECORD=" + cassette}, "get", "-n", "/my-password")
+
+		Eventually(session).Should(Exit(0))
+		Expect(session.Out).To(Say("value: hunter2"))
+
+		recording, err := ioutil.ReadFile(cassette)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(recording)).To(ContainSubstring(`"url": "` + server.URL() + `/api/v1/data?name=%2Fmy-password&versions=1"`))
+		Expect(string(recording)).NotTo(ContainSubstring("hunter2"))
+	})
+
+	It("replays requests from the file named by CREDHUB_REPLAY without contacting the server", func() {
+		session := runCommandWithEnv([]string{"CREDHUB_RECORD=" + cassette}, "get", "-n", "/my-password")
+		Eventually(session).Should(Exit(0))
+		requests := len(server.ReceivedRequests())
+
+		session = runCommandWithEnv([]string{"CREDHUB_REPLAY=" + cassette}, "get", "-n", "/my-password")
+
+		Eventually(session).Should(Exit(0))
+		Expect(session.Out).To(Say("name: /my-password"))
+		Expect(session.Out).To(Say(`value: '\[REDACTED\]'`))
+		Expect(server.ReceivedRequests()).To(HaveLen(requests))
+	})
+
+	It("fails requests that were not recorded", func() {
+		Expect(ioutil.WriteFile(cassette, []byte(`{"interactions":[]}`), 0600)).To(Succeed())
+
+		session := runCommandWithEnv([]string{"CREDHUB_REPLAY=" + cassette}, "get", "-n", "/my-password")
+
+		Eventually(session).Should(Exit(7))
+		Expect(session.Err).To(Say("has no unused interaction for GET /api/v1/data"))
+	})
+})
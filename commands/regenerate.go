@@ -4,11 +4,13 @@ import "github.com/cloudfoundry-incubator/credhub-cli/config"
 
 type RegenerateCommand struct {
 	CredentialIdentifier string `required:"yes" short:"n" long:"name" description:"Selects the credential to regenerate"`
-	OutputJson           bool   `long:"output-json" description:"Return response in JSON format"`
 }
 
 func (cmd RegenerateCommand) Execute([]string) error {
-	cfg := config.ReadConfig()
+	cfg, err := config.ReadConfig()
+	if err != nil {
+		return err
+	}
 
 	credhub, err := initializeCredhubClient(cfg)
 	if err != nil {
@@ -16,8 +18,11 @@ func (cmd RegenerateCommand) Execute([]string) error {
 	}
 
 	credential, err := credhub.Regenerate(cmd.CredentialIdentifier)
+	if err != nil {
+		return err
+	}
 
-	printCredential(cmd.OutputJson, credential)
+	printCredential(CredHub.OutputJson, credential)
 
 	return nil
 }
@@ -22,7 +22,7 @@ var _ = Describe("Regenerate", func() {
 
 	ItRequiresAuthentication("regenerate", "-n", "test-credential")
 	ItRequiresAnAPIToBeSet("regenerate", "-n", "test-credential")
-	ItAutomaticallyLogsIn("POST", "regenerate_response.json", "/api/v1/regenerate", "regenerate", "-n", "test-credential")
+	ItAutomaticallyLogsIn("regenerate", "regenerate", "-n", "test-credential")
 
 	Describe("Regenerating password", func() {
 		It("prints the regenerated password secret in yaml format", func() {
@@ -57,6 +57,20 @@ var _ = Describe("Regenerate", func() {
 		})
 	})
 
+	Describe("Errors", func() {
+		It("exits with the code for the server error", func() {
+			server.RouteToHandler("POST", "/api/v1/regenerate",
+				RespondWith(http.StatusNotFound, `{"error":"The request could not be completed because the credential does not exist or you do not have sufficient authorization."}`),
+			)
+
+			session := runCommand("regenerate", "--name", "my-password-stuffs")
+
+			Eventually(session).Should(Exit(5))
+			Expect(session.Err).To(Say("the credential does not exist"))
+			Expect(session.Out.Contents()).NotTo(ContainSubstring("name:"))
+		})
+	})
+
 	Describe("help", func() {
 		ItBehavesLikeHelp("regenerate", "r", func(session *Session) {
 			Expect(session.Err).To(Say("regenerate"))
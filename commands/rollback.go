@@ -0,0 +1,126 @@
+package commands
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/cloudfoundry-incubator/credhub-cli/config"
+	"github.com/cloudfoundry-incubator/credhub-cli/credhub"
+	"github.com/cloudfoundry-incubator/credhub-cli/credhub/credentials"
+	"github.com/cloudfoundry-incubator/credhub-cli/errors"
+	"github.com/cloudfoundry-incubator/credhub-cli/util"
+)
+
+type RollbackCommand struct {
+	CredentialIdentifier string `short:"n" long:"name" required:"yes" description:"Name of the credential to roll back"`
+	ToId                 string `long:"to-id" description:"ID of the version to roll back to"`
+	Steps                int    `long:"steps" description:"Number of versions to roll back (defaults to 1)"`
+	Force                bool   `long:"force" description:"Roll back without asking for confirmation"`
+}
+
+func (cmd RollbackCommand) Execute([]string) error {
+	if cmd.ToId != "" && cmd.Steps != 0 {
+		return errors.NewRollbackTargetConflictError()
+	}
+	if cmd.ToId == "" && cmd.Steps < 0 {
+		return errors.NewInvalidRollbackStepsError()
+	}
+
+	cfg, err := config.ReadConfig()
+	if err != nil {
+		return err
+	}
+
+	credhubClient, err := initializeCredhubClient(cfg)
+	if err != nil {
+		return err
+	}
+
+	current, target, err := cmd.versions(credhubClient)
+	if err != nil {
+		return err
+	}
+
+	hunks := util.UnifiedDiff(
+		diffDocument(map[string]credentials.Credential{current.Name: current}, current.Name, nil),
+		diffDocument(map[string]credentials.Credential{current.Name: target}, current.Name, nil),
+	)
+	if hunks == "" {
+		fmt.Printf("Version %s of %s has the same value as the current version. Nothing to roll back.\n", target.Id, current.Name)
+		return nil
+	}
+
+	if !cmd.Force {
+		fmt.Printf("--- %s (current version %s)\n+++ %s (version %s)\n%s", current.Name, current.Id, current.Name, target.Id, hunks)
+
+		var answer string
+		promptForInput(fmt.Sprintf("Roll back %s to version %s? [y/N]: ", current.Name, target.Id), &answer)
+		if answer = strings.ToLower(answer); answer != "y" && answer != "yes" {
+			fmt.Println("Rollback cancelled.")
+			return nil
+		}
+	}
+
+	credential, err := credhubClient.SetCredentialIfCurrent(current.Name, target.Type, rollbackValue(target), current.Id)
+	if conflict, ok := err.(*credhub.VersionConflictError); ok {
+		return errors.NewVersionConflictError(conflict.Name, conflict.ExpectedId, conflict.CurrentId)
+	}
+	if err != nil {
+		return err
+	}
+
+	printCredential(CredHub.OutputJson, credential)
+
+	return nil
+}
+
+// versions returns the current version of the credential and the version to
+// roll back to.
+func (cmd RollbackCommand) versions(credhubClient *credhub.CredHub) (credentials.Credential, credentials.Credential, error) {
+	if cmd.ToId != "" {
+		current, err := credhubClient.GetLatestVersion(cmd.CredentialIdentifier)
+		if err != nil {
+			return credentials.Credential{}, credentials.Credential{}, err
+		}
+
+		target, err := credhubClient.GetById(cmd.ToId)
+		if err != nil {
+			return credentials.Credential{}, credentials.Credential{}, err
+		}
+
+		if target.Name != current.Name {
+			return credentials.Credential{}, credentials.Credential{}, errors.NewRollbackVersionMismatchError(cmd.ToId, current.Name)
+		}
+
+		return current, target, nil
+	}
+
+	steps := cmd.Steps
+	if steps == 0 {
+		steps = 1
+	}
+
+	versions, err := credhubClient.GetNVersions(cmd.CredentialIdentifier, steps+1)
+	if err != nil {
+		return credentials.Credential{}, credentials.Credential{}, err
+	}
+
+	if len(versions) <= steps {
+		return credentials.Credential{}, credentials.Credential{}, errors.NewInsufficientVersionsError(cmd.CredentialIdentifier, steps, len(versions))
+	}
+
+	return versions[0], versions[steps], nil
+}
+
+// rollbackValue returns the value to set when restoring credential. A
+// certificate signed by a named CA keeps its ca_name so that it stays linked
+// to the CA, and the server fills in the CA itself.
+func rollbackValue(credential credentials.Credential) interface{} {
+	value := settableValue(credential)
+
+	if certificateCaName(credential) != "" {
+		delete(value.(map[string]interface{}), "ca")
+	}
+
+	return value
+}
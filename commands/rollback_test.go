@@ -0,0 +1,180 @@
+package commands_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gbytes"
+	. "github.com/onsi/gomega/gexec"
+	. "github.com/onsi/gomega/ghttp"
+)
+
+var _ = Describe("Rollback", func() {
+	var sets []map[string]interface{}
+
+	BeforeEach(func() {
+		login()
+
+		sets = nil
+		server.RouteToHandler("PUT", "/api/v1/data", func(w http.ResponseWriter, r *http.Request) {
+			body, _ := ioutil.ReadAll(r.Body)
+			var request map[string]interface{}
+			Expect(json.Unmarshal(body, &request)).To(Succeed())
+			sets = append(sets, request)
+
+			value, _ := json.Marshal(request["value"])
+			w.Write([]byte(versionedCredential("restored-id", request["type"].(string), request["name"].(string), string(value))))
+		})
+	})
+
+	ItRequiresAuthentication("rollback", "-n", "test-credential", "--force")
+
+	It("restores the previous version after confirmation", func() {
+		server.AppendHandlers(
+			CombineHandlers(
+				VerifyRequest("GET", "/api/v1/data", "name=%2Fmy-password&versions=2"),
+				RespondWith(http.StatusOK, `{"data":[`+
+					versionedCredential("new-id", "password", "/my-password", `"bad-password"`)+`,`+
+					versionedCredential("old-id", "password", "/my-password", `"good-password"`)+`]}`),
+			),
+			CombineHandlers(
+				VerifyRequest("GET", "/api/v1/data", "name=%2Fmy-password&versions=1"),
+				RespondWith(http.StatusOK, `{"data":[`+versionedCredential("new-id", "password", "/my-password", `"bad-password"`)+`]}`),
+			),
+		)
+
+		session := runCommandWithStdin(strings.NewReader("y\n"), "rollback", "-n", "/my-password")
+
+		Eventually(session).Should(Exit(0))
+		Expect(session.Out).To(Say(`--- /my-password \(current version new-id\)\n\+\+\+ /my-password \(version old-id\)\n`))
+		Expect(session.Out).To(Say(`-value: bad-password\n\+value: good-password\n`))
+		Expect(session.Out).To(Say(`Roll back /my-password to version old-id\? \[y/N\]: `))
+		Expect(session.Out).To(Say(`id: restored-id`))
+		Expect(sets).To(Equal([]map[string]interface{}{
+			{"name": "/my-password", "type": "password", "value": "good-password", "overwrite": true},
+		}))
+	})
+
+	It("does nothing when the rollback is not confirmed", func() {
+		server.RouteToHandler("GET", "/api/v1/data",
+			RespondWith(http.StatusOK, `{"data":[`+
+				versionedCredential("new-id", "password", "/my-password", `"bad-password"`)+`,`+
+				versionedCredential("old-id", "password", "/my-password", `"good-password"`)+`]}`),
+		)
+
+		session := runCommandWithStdin(strings.NewReader("n\n"), "rollback", "-n", "/my-password")
+
+		Eventually(session).Should(Exit(0))
+		Expect(session.Out).To(Say(`Rollback cancelled.`))
+		Expect(sets).To(BeEmpty())
+	})
+
+	It("rolls back several steps without confirmation when forced", func() {
+		server.AppendHandlers(
+			CombineHandlers(
+				VerifyRequest("GET", "/api/v1/data", "name=%2Fmy-user&versions=3"),
+				RespondWith(http.StatusOK, `{"data":[`+
+					versionedCredential("third-id", "user", "/my-user", `{"username":"admin","password":"third","password_hash":"hash-3"}`)+`,`+
+					versionedCredential("second-id", "user", "/my-user", `{"username":"admin","password":"second","password_hash":"hash-2"}`)+`,`+
+					versionedCredential("first-id", "user", "/my-user", `{"username":"admin","password":"first","password_hash":"hash-1"}`)+`]}`),
+			),
+			CombineHandlers(
+				VerifyRequest("GET", "/api/v1/data", "name=%2Fmy-user&versions=1"),
+				RespondWith(http.StatusOK, `{"data":[`+versionedCredential("third-id", "user", "/my-user", `{"username":"admin","password":"third","password_hash":"hash-3"}`)+`]}`),
+			),
+		)
+
+		session := runCommand("rollback", "-n", "/my-user", "--steps", "2", "--force", "--output-json")
+
+		Eventually(session).Should(Exit(0))
+		Expect(session.Out.Contents()).NotTo(ContainSubstring("[y/N]"))
+		Expect(sets).To(Equal([]map[string]interface{}{
+			{"name": "/my-user", "type": "user", "value": map[string]interface{}{"username": "admin", "password": "first"}, "overwrite": true},
+		}))
+	})
+
+	It("restores a version selected by ID", func() {
+		server.RouteToHandler("GET", "/api/v1/data",
+			CombineHandlers(
+				VerifyRequest("GET", "/api/v1/data", "name=%2Fmy-value&versions=1"),
+				RespondWith(http.StatusOK, `{"data":[`+versionedCredential("new-id", "value", "/my-value", `"new"`)+`]}`),
+			),
+		)
+		server.RouteToHandler("GET", "/api/v1/data/old-id",
+			RespondWith(http.StatusOK, versionedCredential("old-id", "value", "/my-value", `"old"`)),
+		)
+
+		session := runCommand("rollback", "-n", "/my-value", "--to-id", "old-id", "--force")
+
+		Eventually(session).Should(Exit(0))
+		Expect(sets).To(Equal([]map[string]interface{}{
+			{"name": "/my-value", "type": "value", "value": "old", "overwrite": true},
+		}))
+	})
+
+	It("does not roll back a credential that changed after it was read", func() {
+		server.AppendHandlers(
+			RespondWith(http.StatusOK, `{"data":[`+
+				versionedCredential("new-id", "password", "/my-password", `"bad-password"`)+`,`+
+				versionedCredential("old-id", "password", "/my-password", `"good-password"`)+`]}`),
+			RespondWith(http.StatusOK, `{"data":[`+versionedCredential("newer-id", "password", "/my-password", `"other-password"`)+`]}`),
+		)
+
+		session := runCommand("rollback", "-n", "/my-password", "--force")
+
+		Eventually(session).Should(Exit(6))
+		Expect(session.Err).To(Say("The credential '/my-password' was not set because its current version is 'newer-id', not 'new-id'."))
+		Expect(sets).To(BeEmpty())
+	})
+
+	It("refuses a version of another credential", func() {
+		server.RouteToHandler("GET", "/api/v1/data",
+			RespondWith(http.StatusOK, `{"data":[`+versionedCredential("new-id", "value", "/my-value", `"new"`)+`]}`),
+		)
+		server.RouteToHandler("GET", "/api/v1/data/other-id",
+			RespondWith(http.StatusOK, versionedCredential("other-id", "value", "/other-value", `"old"`)),
+		)
+
+		session := runCommand("rollback", "-n", "/my-value", "--to-id", "other-id", "--force")
+
+		Eventually(session).Should(Exit(2))
+		Expect(session.Err).To(Say("The version 'other-id' is not a version of the credential '/my-value'."))
+		Expect(sets).To(BeEmpty())
+	})
+
+	It("errors when there are not enough versions", func() {
+		server.RouteToHandler("GET", "/api/v1/data",
+			RespondWith(http.StatusOK, `{"data":[`+versionedCredential("only-id", "value", "/my-value", `"only"`)+`]}`),
+		)
+
+		session := runCommand("rollback", "-n", "/my-value", "--force")
+
+		Eventually(session).Should(Exit(2))
+		Expect(session.Err).To(Say(`The credential '/my-value' cannot be rolled back 1 step\(s\) because it only has 1 version\(s\).`))
+	})
+
+	It("does not set a version with the same value", func() {
+		server.RouteToHandler("GET", "/api/v1/data",
+			RespondWith(http.StatusOK, `{"data":[`+
+				versionedCredential("new-id", "value", "/my-value", `"same"`)+`,`+
+				versionedCredential("old-id", "value", "/my-value", `"same"`)+`]}`),
+		)
+
+		session := runCommand("rollback", "-n", "/my-value", "--force")
+
+		Eventually(session).Should(Exit(0))
+		Expect(session.Out).To(Say(`Version old-id of /my-value has the same value as the current version. Nothing to roll back.`))
+		Expect(sets).To(BeEmpty())
+	})
+
+	It("rejects both --to-id and --steps", func() {
+		session := runCommand("rollback", "-n", "/my-value", "--to-id", "old-id", "--steps", "2")
+
+		Eventually(session).Should(Exit(2))
+		Expect(session.Err).To(Say("Only one of --to-id and --steps may be provided."))
+	})
+})
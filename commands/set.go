@@ -12,7 +12,6 @@ import (
 	"encoding/json"
 
 	"github.com/cloudfoundry-incubator/credhub-cli/credhub"
-	"github.com/cloudfoundry-incubator/credhub-cli/credhub/credentials"
 	"github.com/cloudfoundry-incubator/credhub-cli/credhub/credentials/values"
 	"github.com/cloudfoundry-incubator/credhub-cli/errors"
 	"github.com/cloudfoundry-incubator/credhub-cli/util"
@@ -30,7 +29,8 @@ type SetCommand struct {
 	Public               string `short:"u" long:"public" description:"[SSH, RSA] Sets the public key from file or value"`
 	Username             string `short:"z" long:"username" description:"[User] Sets the username value of the credential"`
 	Password             string `short:"w" long:"password" description:"[Password, User] Sets the password value of the credential"`
-	OutputJson           bool   `          long:"output-json" description:"Return response in JSON format"`
+	Mode                 string `          long:"mode" description:"How to handle an existing credential: 'overwrite' (default), 'no-overwrite' or 'converge'"`
+	IfVersion            string `          long:"if-version" description:"Only set the credential if its current version has this ID"`
 }
 
 func (cmd SetCommand) Execute([]string) error {
@@ -48,7 +48,10 @@ func (cmd SetCommand) Execute([]string) error {
 		promptForInput("password: ", &cmd.Password)
 	}
 
-	cfg := config.ReadConfig()
+	cfg, err := config.ReadConfig()
+	if err != nil {
+		return err
+	}
 
 	credhubClient, err := initializeCredhubClient(cfg)
 	if err != nil {
@@ -67,7 +70,7 @@ func (cmd SetCommand) Execute([]string) error {
 		return err
 	}
 
-	printCredential(cmd.OutputJson, credential)
+	printCredential(CredHub.OutputJson, credential)
 
 	return nil
 }
@@ -75,6 +78,23 @@ func (cmd SetCommand) Execute([]string) error {
 func MakeRequest(cmd SetCommand, config config.Config, credhubClient *credhub.CredHub) (interface{}, error) {
 	var output interface{}
 	var responseError error
+	var value interface{}
+
+	mode, err := writeMode(cmd.Mode, cmd.NoOverwrite)
+	if err != nil {
+		return nil, err
+	}
+
+	if cmd.IfVersion != "" {
+		if err := credhubClient.CheckCurrentVersion(cmd.CredentialIdentifier, cmd.IfVersion); err != nil {
+			if conflict, ok := err.(*credhub.VersionConflictError); ok {
+				return nil, errors.NewVersionConflictError(conflict.Name, conflict.ExpectedId, conflict.CurrentId)
+			}
+			return nil, err
+		}
+	}
+
+	credType := cmd.Type
 
 	if cmd.Type == "ssh" || cmd.Type == "rsa" {
 		publicKey, err := util.ReadFileOrStringFromField(cmd.Public)
@@ -87,19 +107,15 @@ func MakeRequest(cmd SetCommand, config config.Config, credhubClient *credhub.Cr
 			return nil, err
 		}
 		if cmd.Type == "ssh" {
-			value := values.SSH{}
-			value.PublicKey = publicKey
-			value.PrivateKey = privateKey
-			var sshCredential credentials.SSH
-			sshCredential, responseError = credhubClient.SetSSH(cmd.CredentialIdentifier, value, !cmd.NoOverwrite)
-			output = interface{}(sshCredential)
+			sshValue := values.SSH{}
+			sshValue.PublicKey = publicKey
+			sshValue.PrivateKey = privateKey
+			value = sshValue
 		} else {
-			value := values.RSA{}
-			value.PublicKey = publicKey
-			value.PrivateKey = privateKey
-			var rsaCredential credentials.RSA
-			rsaCredential, responseError = credhubClient.SetRSA(cmd.CredentialIdentifier, value, !cmd.NoOverwrite)
-			output = interface{}(rsaCredential)
+			rsaValue := values.RSA{}
+			rsaValue.PublicKey = publicKey
+			rsaValue.PrivateKey = privateKey
+			value = rsaValue
 		}
 	} else if cmd.Type == "certificate" {
 
@@ -118,38 +134,35 @@ func MakeRequest(cmd SetCommand, config config.Config, credhubClient *credhub.Cr
 			return nil, err
 		}
 
-		value := values.Certificate{}
-		value.Certificate = certificate
-		value.PrivateKey = privateKey
-		value.Ca = root
-		value.CaName = cmd.CaName
-		var certificateCredential credentials.Certificate
-		certificateCredential, responseError = credhubClient.SetCertificate(cmd.CredentialIdentifier, value, !cmd.NoOverwrite)
-		output = interface{}(certificateCredential)
+		certificateValue := values.Certificate{}
+		certificateValue.Certificate = certificate
+		certificateValue.PrivateKey = privateKey
+		certificateValue.Ca = root
+		certificateValue.CaName = cmd.CaName
+		value = certificateValue
 	} else if cmd.Type == "user" {
-		value := values.User{}
+		userValue := values.User{}
 		if cmd.Username != "" {
-			value.Username = &cmd.Username
+			userValue.Username = &cmd.Username
 		}
-		value.Password = cmd.Password
-		var userCredential credentials.User
-		userCredential, responseError = credhubClient.SetUser(cmd.CredentialIdentifier, value, !cmd.NoOverwrite)
-		output = interface{}(userCredential)
+		userValue.Password = cmd.Password
+		value = userValue
 
 	} else if cmd.Type == "password" {
-		var passwordCredential credentials.Password
-		passwordCredential, responseError = credhubClient.SetPassword(cmd.CredentialIdentifier, values.Password(cmd.Password), !cmd.NoOverwrite)
-		output = interface{}(passwordCredential)
+		value = values.Password(cmd.Password)
 	} else if cmd.Type == "json" {
-		var jsonCredential credentials.JSON
 		var unmarshalled values.JSON
 		json.Unmarshal([]byte(cmd.Value), &unmarshalled)
-		jsonCredential, responseError = credhubClient.SetJSON(cmd.CredentialIdentifier, unmarshalled, !cmd.NoOverwrite)
-		output = interface{}(jsonCredential)
+		value = unmarshalled
+	} else {
+		credType = "value"
+		value = values.Value(cmd.Value)
+	}
+
+	if mode != "" {
+		output, responseError = credhubClient.SetCredentialWithMode(cmd.CredentialIdentifier, credType, value, mode)
 	} else {
-		var valueCredential credentials.Value
-		valueCredential, responseError = credhubClient.SetValue(cmd.CredentialIdentifier, values.Value(cmd.Value), !cmd.NoOverwrite)
-		output = interface{}(valueCredential)
+		output, responseError = setTypedCredential(credhubClient, cmd.CredentialIdentifier, value, !cmd.NoOverwrite)
 	}
 
 	if responseError != nil {
@@ -159,6 +172,25 @@ func MakeRequest(cmd SetCommand, config config.Config, credhubClient *credhub.Cr
 	return &output, nil
 }
 
+func setTypedCredential(credhubClient *credhub.CredHub, name string, value interface{}, overwrite bool) (interface{}, error) {
+	switch typed := value.(type) {
+	case values.SSH:
+		return credhubClient.SetSSH(name, typed, overwrite)
+	case values.RSA:
+		return credhubClient.SetRSA(name, typed, overwrite)
+	case values.Certificate:
+		return credhubClient.SetCertificate(name, typed, overwrite)
+	case values.User:
+		return credhubClient.SetUser(name, typed, overwrite)
+	case values.Password:
+		return credhubClient.SetPassword(name, typed, overwrite)
+	case values.JSON:
+		return credhubClient.SetJSON(name, typed, overwrite)
+	default:
+		return credhubClient.SetValue(name, value.(values.Value), overwrite)
+	}
+}
+
 func promptForInput(prompt string, value *string) {
 	fmt.Printf(prompt)
 	reader := bufio.NewReader(os.Stdin)
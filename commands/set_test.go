@@ -27,13 +27,13 @@ var _ = Describe("Set", func() {
 
 	ItRequiresAuthentication("set", "-n", "test-credential", "-t", "password", "-w", "value")
 	ItRequiresAnAPIToBeSet("set", "-n", "test-credential", "-t", "password", "-w", "value")
-	ItAutomaticallyLogsIn("PUT", "set_response.json", "/api/v1/data", "set", "-n", "test-credential", "-t", "password", "-w", "test-value")
+	ItAutomaticallyLogsIn("set", "set", "-n", "test-credential", "-t", "password", "-w", "test-value")
 
 	Describe("not specifying type", func() {
 		It("returns an error", func() {
 			session := runCommand("set", "-n", "my-password", "-w", "potatoes")
 
-			Eventually(session).Should(Exit(1))
+			Eventually(session).Should(Exit(2))
 			Eventually(session.Err).Should(Say("A type must be specified when setting a credential. Valid types include 'value', 'json', 'password', 'user', 'certificate', 'ssh' and 'rsa'."))
 		})
 	})
@@ -591,6 +591,66 @@ var _ = Describe("Set", func() {
 		})
 	})
 
+	Describe("setting a secret only if it is at an expected version", func() {
+		It("sets the secret when the current version matches", func() {
+			server.AppendHandlers(
+				CombineHandlers(
+					VerifyRequest("GET", "/api/v1/data", "name=my-password&versions=1"),
+					RespondWith(http.StatusOK, fmt.Sprintf(STRING_CREDENTIAL_ARRAY_RESPONSE_JSON, "password", "my-password", "old")),
+				),
+			)
+			SetupPutValueServer("my-password", "password", "potatoes")
+
+			session := runCommand("set", "-n", "my-password", "-t", "password", "-w", "potatoes", "--if-version", UUID)
+
+			Eventually(session).Should(Exit(0))
+			Eventually(session.Out).Should(Say("value: potatoes"))
+		})
+
+		It("does not set the secret when the current version has moved", func() {
+			server.AppendHandlers(
+				RespondWith(http.StatusOK, fmt.Sprintf(STRING_CREDENTIAL_ARRAY_RESPONSE_JSON, "password", "my-password", "old")),
+			)
+
+			session := runCommand("set", "-n", "my-password", "-t", "password", "-w", "potatoes", "--if-version", "stale-id")
+
+			Eventually(session).Should(Exit(6))
+			Expect(session.Err).To(Say("The credential 'my-password' was not set because its current version is '" + UUID + "', not 'stale-id'."))
+			Expect(server.ReceivedRequests()).To(HaveLen(1))
+		})
+	})
+
+	Describe("setting a secret with a write mode", func() {
+		It("sends the mode instead of overwrite", func() {
+			server.AppendHandlers(
+				CombineHandlers(
+					VerifyRequest("PUT", "/api/v1/data"),
+					VerifyJSON(`{"type":"password","name":"my-password","value":"potatoes","mode":"converge"}`),
+					RespondWith(http.StatusOK, fmt.Sprintf(STRING_CREDENTIAL_RESPONSE_JSON, "password", "my-password", "potatoes")),
+				),
+			)
+
+			session := runCommand("set", "-n", "my-password", "-t", "password", "-w", "potatoes", "--mode", "converge")
+
+			Eventually(session).Should(Exit(0))
+			Eventually(session.Out).Should(Say("value: potatoes"))
+		})
+
+		It("rejects an unknown mode", func() {
+			session := runCommand("set", "-n", "my-password", "-t", "password", "-w", "potatoes", "--mode", "sometimes")
+
+			Eventually(session).Should(Exit(2))
+			Expect(session.Err).To(Say("The mode 'sometimes' is not supported. Valid modes are 'overwrite', 'no-overwrite' and 'converge'."))
+		})
+
+		It("cannot be combined with --no-overwrite", func() {
+			session := runCommand("set", "-n", "my-password", "-t", "password", "-w", "potatoes", "--mode", "converge", "--no-overwrite")
+
+			Eventually(session).Should(Exit(2))
+			Expect(session.Err).To(Say("The --mode and --no-overwrite flags cannot be combined."))
+		})
+	})
+
 	Describe("Help", func() {
 		It("short flags", func() {
 			Expect(commands.SetCommand{}).To(SatisfyAll(
@@ -613,7 +673,7 @@ var _ = Describe("Set", func() {
 		It("displays missing 'n' option as required parameter", func() {
 			session := runCommand("set", "-v", "potatoes")
 
-			Eventually(session).Should(Exit(1))
+			Eventually(session).Should(Exit(2))
 			if runtime.GOOS == "windows" {
 				Expect(session.Err).To(Say("the required flag `/n, /name' was not specified"))
 			} else {
@@ -628,7 +688,7 @@ var _ = Describe("Set", func() {
 
 			session := runCommand("set", "-n", "my-value", "-t", "value", "-v", "tomatoes")
 
-			Eventually(session).Should(Exit(1))
+			Eventually(session).Should(Exit(2))
 
 			Expect(session.Err).To(Say("test error"))
 		})
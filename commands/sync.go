@@ -0,0 +1,265 @@
+package commands
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/cloudfoundry-incubator/credhub-cli/credhub"
+	"github.com/cloudfoundry-incubator/credhub-cli/credhub/credentials"
+	"github.com/cloudfoundry-incubator/credhub-cli/errors"
+)
+
+type SyncCommand struct {
+	FromProfile      string `long:"from-profile" required:"yes" description:"Profile to copy credentials from"`
+	ToProfile        string `long:"to-profile" required:"yes" description:"Profile to copy credentials to"`
+	Path             string `short:"p" long:"path" required:"yes" description:"Path of the credentials to sync"`
+	DryRun           bool   `long:"dry-run" description:"Print the change plan without changing the destination"`
+	DeleteExtraneous bool   `long:"delete-extraneous" description:"Delete credentials under the path that only exist on the destination"`
+}
+
+const (
+	syncCreate = "+"
+	syncUpdate = "~"
+	syncDelete = "-"
+)
+
+type syncChange struct {
+	action     string
+	name       string
+	credential credentials.Credential
+}
+
+func (cmd SyncCommand) Execute([]string) error {
+	if cmd.FromProfile == cmd.ToProfile {
+		return errors.NewSyncSameProfileError()
+	}
+
+	source, err := profileCredhubClient(cmd.FromProfile)
+	if err != nil {
+		return err
+	}
+
+	destination, err := profileCredhubClient(cmd.ToProfile)
+	if err != nil {
+		return err
+	}
+
+	sourceCredentials, err := latestVersionsByPath(source, cmd.Path)
+	if err != nil {
+		return err
+	}
+
+	destinationCredentials, err := latestVersionsByPath(destination, cmd.Path)
+	if err != nil {
+		return err
+	}
+
+	caNames, err := syncedCaNames(destination, sourceCredentials)
+	if err != nil {
+		return err
+	}
+
+	plan := cmd.plan(sourceCredentials, destinationCredentials, caNames)
+
+	if len(plan) == 0 {
+		fmt.Printf("Profile '%s' is already in sync with profile '%s' under %s.\n", cmd.ToProfile, cmd.FromProfile, cmd.Path)
+		return nil
+	}
+
+	printSyncPlan(cmd, plan)
+
+	if cmd.DryRun {
+		fmt.Println("Dry run: no changes were made.")
+		return nil
+	}
+
+	for _, change := range plan {
+		if change.action == syncDelete {
+			err = destination.Delete(change.name)
+		} else {
+			_, err = destination.SetCredential(change.name, change.credential.Type, syncValue(change.credential, caNames), true)
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+
+	fmt.Println("Sync complete.")
+
+	return nil
+}
+
+// plan lists the changes that make destination match source. A certificate's
+// CA is written before any certificate that references it by ca_name.
+// Credentials are compared in the form they are written to the destination.
+func (cmd SyncCommand) plan(source, destination map[string]credentials.Credential, caNames map[string]bool) []syncChange {
+	var plan []syncChange
+
+	for _, name := range caFirstOrder(source) {
+		credential := source[name]
+
+		existing, ok := destination[name]
+		switch {
+		case !ok:
+			plan = append(plan, syncChange{syncCreate, name, credential})
+		case existing.Type != credential.Type || !reflect.DeepEqual(syncValue(existing, caNames), syncValue(credential, caNames)):
+			plan = append(plan, syncChange{syncUpdate, name, credential})
+		}
+	}
+
+	if cmd.DeleteExtraneous {
+		for _, name := range sortedNames(destination) {
+			if _, ok := source[name]; !ok {
+				plan = append(plan, syncChange{action: syncDelete, name: name})
+			}
+		}
+	}
+
+	return plan
+}
+
+// caFirstOrder returns the names of credentialsByName sorted, except that a
+// certificate's CA comes before any certificate that references it by
+// ca_name.
+func caFirstOrder(credentialsByName map[string]credentials.Credential) []string {
+	var order []string
+	visited := map[string]bool{}
+
+	var visit func(name string)
+	visit = func(name string) {
+		if visited[name] {
+			return
+		}
+		visited[name] = true
+
+		if caName := certificateCaName(credentialsByName[name]); caName != "" {
+			if _, ok := credentialsByName[caName]; ok {
+				visit(caName)
+			}
+		}
+
+		order = append(order, name)
+	}
+
+	for _, name := range sortedNames(credentialsByName) {
+		visit(name)
+	}
+
+	return order
+}
+
+func printSyncPlan(cmd SyncCommand, plan []syncChange) {
+	counts := map[string]int{}
+
+	fmt.Printf("Changes to sync profile '%s' to profile '%s' under %s:\n", cmd.FromProfile, cmd.ToProfile, cmd.Path)
+	for _, change := range plan {
+		counts[change.action]++
+		if change.action == syncDelete {
+			fmt.Printf("  %s %s\n", change.action, change.name)
+		} else {
+			fmt.Printf("  %s %s (%s)\n", change.action, change.name, change.credential.Type)
+		}
+	}
+
+	fmt.Printf("Create: %d, Update: %d, Delete: %d\n", counts[syncCreate], counts[syncUpdate], counts[syncDelete])
+}
+
+// syncedCaNames returns the CA names that certificates in source can keep
+// referencing on the destination: those of CAs synced along with them, and
+// those of CAs outside the path that the destination already has with the same
+// certificate.
+func syncedCaNames(destination *credhub.CredHub, source map[string]credentials.Credential) (map[string]bool, error) {
+	caNames := map[string]bool{}
+
+	for _, name := range sortedNames(source) {
+		caName := certificateCaName(source[name])
+		if caName == "" || caNames[caName] {
+			continue
+		}
+
+		if _, ok := source[caName]; ok {
+			caNames[caName] = true
+			continue
+		}
+
+		ca, err := destination.GetLatestVersion(caName)
+		if isNotFoundError(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		caValue, _ := ca.Value.(map[string]interface{})
+		certificate, _ := source[name].Value.(map[string]interface{})
+		if caValue["certificate"] != nil && caValue["certificate"] == certificate["ca"] {
+			caNames[caName] = true
+		}
+	}
+
+	return caNames, nil
+}
+
+// syncValue returns the value to set for credential on the destination. A
+// certificate keeps its ca_name only when the destination has that CA;
+// otherwise its CA is written out literally.
+func syncValue(credential credentials.Credential, caNames map[string]bool) interface{} {
+	value := settableValue(credential)
+
+	caName := certificateCaName(credential)
+	if caName == "" {
+		return value
+	}
+
+	certificate := value.(map[string]interface{})
+	if caNames[caName] {
+		delete(certificate, "ca")
+	} else {
+		delete(certificate, "ca_name")
+	}
+
+	return certificate
+}
+
+func certificateCaName(credential credentials.Credential) string {
+	if credential.Type != "certificate" {
+		return ""
+	}
+
+	value, _ := credential.Value.(map[string]interface{})
+	caName, _ := value["ca_name"].(string)
+
+	return caName
+}
+
+// latestVersionsByPath returns the latest version of every credential under
+// path, keyed by name.
+func latestVersionsByPath(credhubClient *credhub.CredHub, path string) (map[string]credentials.Credential, error) {
+	results, err := credhubClient.FindByPath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	latest := map[string]credentials.Credential{}
+	for _, result := range results.Credentials {
+		credential, err := credhubClient.GetLatestVersion(result.Name)
+		if err != nil {
+			return nil, err
+		}
+		latest[result.Name] = credential
+	}
+
+	return latest, nil
+}
+
+func sortedNames(credentialsByName map[string]credentials.Credential) []string {
+	names := make([]string, 0, len(credentialsByName))
+	for name := range credentialsByName {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
@@ -0,0 +1,171 @@
+package commands_test
+
+import (
+	"net/http"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gbytes"
+	. "github.com/onsi/gomega/gexec"
+	. "github.com/onsi/gomega/ghttp"
+)
+
+var _ = Describe("Sync", func() {
+	var (
+		otherServer *Server
+		source      *credentialStore
+		destination *credentialStore
+	)
+
+	BeforeEach(func() {
+		login()
+
+		otherServer = NewTlsServer("../test/server-tls-cert.pem", "../test/server-tls-key.pem")
+		SetupServers(otherServer, authServer)
+		Eventually(runCommand("target", "add", "dr", "-s", otherServer.URL(), "--ca-cert", "../test/server-tls-ca.pem", "--ca-cert", "../test/auth-tls-ca.pem")).Should(Exit(0))
+
+		authServer.AppendHandlers(
+			RespondWith(http.StatusOK, `{"access_token":"dr-access-token","refresh_token":"dr-refresh-token","token_type":"password","expires_in":123456789}`),
+		)
+		Eventually(runCommand("--profile", "dr", "login", "-u", "test-username", "-p", "test-password")).Should(Exit(0))
+
+		source = newCredentialStore(server, map[string]string{
+			"/shared/same":    valueCredential("value", "/shared/same", "same-value"),
+			"/shared/changed": valueCredential("password", "/shared/changed", "new-password"),
+			"/shared/created": `{"type":"user","id":"` + UUID + `","name":"/shared/created","version_created_at":"` + TIMESTAMP + `","value":{"username":"admin","password":"secret","password_hash":"hash"}}`,
+		})
+		destination = newCredentialStore(otherServer, map[string]string{
+			"/shared/same":    valueCredential("value", "/shared/same", "same-value"),
+			"/shared/changed": valueCredential("password", "/shared/changed", "old-password"),
+			"/shared/extra":   valueCredential("value", "/shared/extra", "extra-value"),
+		})
+	})
+
+	AfterEach(func() {
+		otherServer.Close()
+	})
+
+	It("prints the plan without changing anything on a dry run", func() {
+		session := runCommand("sync", "--from-profile", "default", "--to-profile", "dr", "--path", "/shared", "--delete-extraneous", "--dry-run")
+
+		Eventually(session).Should(Exit(0))
+		Expect(session.Out).To(Say(`Changes to sync profile 'default' to profile 'dr' under /shared:`))
+		Expect(session.Out).To(Say(`  ~ /shared/changed \(password\)`))
+		Expect(session.Out).To(Say(`  \+ /shared/created \(user\)`))
+		Expect(session.Out).To(Say(`  - /shared/extra`))
+		Expect(session.Out).To(Say(`Create: 1, Update: 1, Delete: 1`))
+		Expect(session.Out).To(Say(`Dry run: no changes were made.`))
+
+		Expect(destination.sets).To(BeEmpty())
+		Expect(destination.deletes).To(BeEmpty())
+	})
+
+	It("creates and updates credentials preserving their types", func() {
+		session := runCommand("sync", "--from-profile", "default", "--to-profile", "dr", "--path", "/shared")
+
+		Eventually(session).Should(Exit(0))
+		Expect(session.Out).To(Say(`Create: 1, Update: 1, Delete: 0`))
+		Expect(session.Out).To(Say(`Sync complete.`))
+
+		Expect(destination.sets).To(ConsistOf(
+			map[string]interface{}{"name": "/shared/changed", "type": "password", "value": "new-password", "overwrite": true},
+			map[string]interface{}{"name": "/shared/created", "type": "user", "value": map[string]interface{}{"username": "admin", "password": "secret"}, "overwrite": true},
+		))
+		Expect(destination.deletes).To(BeEmpty())
+		Expect(source.sets).To(BeEmpty())
+	})
+
+	It("deletes extraneous credentials when asked to", func() {
+		session := runCommand("sync", "--from-profile", "default", "--to-profile", "dr", "--path", "/shared", "--delete-extraneous")
+
+		Eventually(session).Should(Exit(0))
+		Expect(destination.deletes).To(Equal([]string{"/shared/extra"}))
+	})
+
+	It("writes a certificate's CA before certificates that reference it", func() {
+		source.credentials = map[string]string{
+			"/shared/a-cert": `{"type":"certificate","id":"` + UUID + `","name":"/shared/a-cert","version_created_at":"` + TIMESTAMP + `","value":{"ca_name":"/shared/z-ca","ca":"ca-pem","certificate":"cert-pem","private_key":"key-pem"}}`,
+			"/shared/z-ca":   `{"type":"certificate","id":"` + UUID + `","name":"/shared/z-ca","version_created_at":"` + TIMESTAMP + `","value":{"ca":"ca-pem","certificate":"ca-pem","private_key":"ca-key-pem"}}`,
+		}
+		destination.credentials = map[string]string{}
+
+		session := runCommand("sync", "--from-profile", "default", "--to-profile", "dr", "--path", "/shared")
+
+		Eventually(session).Should(Exit(0))
+		Expect(destination.sets).To(HaveLen(2))
+		Expect(destination.sets[0]["name"]).To(Equal("/shared/z-ca"))
+		Expect(destination.sets[1]["value"]).To(Equal(map[string]interface{}{"ca_name": "/shared/z-ca", "certificate": "cert-pem", "private_key": "key-pem"}))
+	})
+
+	It("does not rewrite a certificate whose CA outside the path was written out literally", func() {
+		source.credentials = map[string]string{
+			"/shared/cert": `{"type":"certificate","id":"` + UUID + `","name":"/shared/cert","version_created_at":"` + TIMESTAMP + `","value":{"ca_name":"/other/ca","ca":"ca-pem","certificate":"cert-pem","private_key":"key-pem"}}`,
+		}
+		destination.credentials = map[string]string{
+			"/shared/cert": `{"type":"certificate","id":"` + UUID + `","name":"/shared/cert","version_created_at":"` + TIMESTAMP + `","value":{"ca":"ca-pem","certificate":"cert-pem","private_key":"key-pem"}}`,
+		}
+
+		session := runCommand("sync", "--from-profile", "default", "--to-profile", "dr", "--path", "/shared")
+
+		Eventually(session).Should(Exit(0))
+		Expect(session.Out).To(Say(`Profile 'dr' is already in sync with profile 'default' under /shared.`))
+		Expect(destination.sets).To(BeEmpty())
+	})
+
+	It("keeps ca_name when the destination already has the CA outside the path", func() {
+		source.credentials = map[string]string{
+			"/shared/cert": `{"type":"certificate","id":"` + UUID + `","name":"/shared/cert","version_created_at":"` + TIMESTAMP + `","value":{"ca_name":"/other/ca","ca":"ca-pem","certificate":"cert-pem","private_key":"key-pem"}}`,
+		}
+		destination.credentials = map[string]string{
+			"/other/ca": `{"type":"certificate","id":"` + UUID + `","name":"/other/ca","version_created_at":"` + TIMESTAMP + `","value":{"ca":"ca-pem","certificate":"ca-pem","private_key":"ca-key-pem"}}`,
+		}
+
+		session := runCommand("sync", "--from-profile", "default", "--to-profile", "dr", "--path", "/shared")
+
+		Eventually(session).Should(Exit(0))
+		Expect(destination.sets).To(Equal([]map[string]interface{}{
+			{"name": "/shared/cert", "type": "certificate", "value": map[string]interface{}{"ca_name": "/other/ca", "certificate": "cert-pem", "private_key": "key-pem"}, "overwrite": true},
+		}))
+	})
+
+	It("writes the CA out literally when the destination's CA outside the path differs", func() {
+		source.credentials = map[string]string{
+			"/shared/cert": `{"type":"certificate","id":"` + UUID + `","name":"/shared/cert","version_created_at":"` + TIMESTAMP + `","value":{"ca_name":"/other/ca","ca":"ca-pem","certificate":"cert-pem","private_key":"key-pem"}}`,
+		}
+		destination.credentials = map[string]string{
+			"/other/ca": `{"type":"certificate","id":"` + UUID + `","name":"/other/ca","version_created_at":"` + TIMESTAMP + `","value":{"ca":"other-ca-pem","certificate":"other-ca-pem","private_key":"ca-key-pem"}}`,
+		}
+
+		session := runCommand("sync", "--from-profile", "default", "--to-profile", "dr", "--path", "/shared")
+
+		Eventually(session).Should(Exit(0))
+		Expect(destination.sets).To(Equal([]map[string]interface{}{
+			{"name": "/shared/cert", "type": "certificate", "value": map[string]interface{}{"ca": "ca-pem", "certificate": "cert-pem", "private_key": "key-pem"}, "overwrite": true},
+		}))
+	})
+
+	It("reports when the destination is already in sync", func() {
+		source.credentials = map[string]string{"/shared/same": valueCredential("value", "/shared/same", "same-value")}
+		destination.credentials = map[string]string{"/shared/same": valueCredential("value", "/shared/same", "same-value")}
+
+		session := runCommand("sync", "--from-profile", "default", "--to-profile", "dr", "--path", "/shared")
+
+		Eventually(session).Should(Exit(0))
+		Expect(session.Out).To(Say(`Profile 'dr' is already in sync with profile 'default' under /shared.`))
+	})
+
+	It("errors when the source and destination profiles are the same", func() {
+		session := runCommand("sync", "--from-profile", "dr", "--to-profile", "dr", "--path", "/shared")
+
+		Eventually(session).Should(Exit(2))
+		Expect(session.Err).To(Say("The source and destination profiles must be different."))
+		Expect(destination.sets).To(BeEmpty())
+	})
+
+	It("errors when a profile does not exist", func() {
+		session := runCommand("sync", "--from-profile", "default", "--to-profile", "missing", "--path", "/shared")
+
+		Eventually(session).Should(Exit(5))
+		Expect(session.Err).To(Say("The profile 'missing' does not exist."))
+	})
+})
@@ -0,0 +1,141 @@
+package commands
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/cloudfoundry-incubator/credhub-cli/config"
+	"github.com/cloudfoundry-incubator/credhub-cli/errors"
+)
+
+type TargetCommand struct {
+	Add    TargetAddCommand    `command:"add"    description:"Add a named target profile"`
+	List   TargetListCommand   `command:"list"   description:"List the configured target profiles"`
+	Use    TargetUseCommand    `command:"use"    description:"Set the target profile used by future commands"`
+	Remove TargetRemoveCommand `command:"remove" description:"Remove a target profile and its stored tokens"`
+}
+
+type TargetNameArgs struct {
+	Name string `positional-arg-name:"NAME" required:"yes" description:"Name of the target profile"`
+}
+
+type TargetAddCommand struct {
+	Args              TargetNameArgs `positional-args:"yes" required:"yes"`
+	ServerUrl         string         `short:"s" long:"server" description:"URI of API server to target"`
+	CaCerts           []string       `long:"ca-cert" description:"Trusted CA for API and UAA TLS connections. Multiple flags may be provided."`
+	SkipTlsValidation bool           `long:"skip-tls-validation" description:"Skip certificate validation of the API endpoint. Not recommended!"`
+}
+
+type TargetListCommand struct{}
+
+type TargetUseCommand struct {
+	Args TargetNameArgs `positional-args:"yes" required:"yes"`
+}
+
+type TargetRemoveCommand struct {
+	Args TargetNameArgs `positional-args:"yes" required:"yes"`
+}
+
+func (cmd TargetAddCommand) Execute([]string) error {
+	profiles, err := config.ReadProfiles()
+	if err != nil {
+		return err
+	}
+
+	if _, ok := profiles.Profiles[cmd.Args.Name]; ok {
+		return errors.NewProfileAlreadyExistsError(cmd.Args.Name)
+	}
+
+	cfg := config.Config{}
+
+	if cmd.ServerUrl != "" {
+		err := SetTarget(&cfg, cmd.ServerUrl, cmd.CaCerts, cmd.SkipTlsValidation)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = config.UpdateProfiles(func(profiles *config.Profiles) error {
+		if _, ok := profiles.Profiles[cmd.Args.Name]; ok {
+			return errors.NewProfileAlreadyExistsError(cmd.Args.Name)
+		}
+
+		profiles.Profiles[cmd.Args.Name] = cfg
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Added profile '%s'\n", cmd.Args.Name)
+
+	return nil
+}
+
+func (cmd TargetListCommand) Execute([]string) error {
+	profiles, err := config.ReadProfiles()
+	if err != nil {
+		return err
+	}
+
+	active := profiles.ActiveProfile()
+
+	names := []string{}
+	for name := range profiles.Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		marker := " "
+		if name == active {
+			marker = "*"
+		}
+
+		apiURL := profiles.Profiles[name].ApiURL
+		if apiURL == "" {
+			apiURL = "(no API target set)"
+		}
+
+		fmt.Printf("%s %s\t%s\n", marker, name, apiURL)
+	}
+
+	return nil
+}
+
+func (cmd TargetUseCommand) Execute([]string) error {
+	err := config.UpdateProfiles(func(profiles *config.Profiles) error {
+		if _, ok := profiles.Profiles[cmd.Args.Name]; !ok {
+			return errors.NewProfileNotFoundError(cmd.Args.Name)
+		}
+
+		profiles.CurrentProfile = cmd.Args.Name
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Using profile '%s'\n", cmd.Args.Name)
+
+	return nil
+}
+
+func (cmd TargetRemoveCommand) Execute([]string) error {
+	removed, err := config.RemoveProfile(cmd.Args.Name)
+	if err != nil {
+		return err
+	}
+
+	RevokeTokenIfNecessary(removed)
+
+	fmt.Printf("Removed profile '%s'\n", cmd.Args.Name)
+
+	return nil
+}
+
+func init() {
+	CredHub.Profile = func(name string) {
+		config.SetProfile(name)
+	}
+}
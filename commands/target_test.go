@@ -0,0 +1,192 @@
+package commands_test
+
+import (
+	"io/ioutil"
+	"net/http"
+	"os"
+
+	"github.com/cloudfoundry-incubator/credhub-cli/config"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gbytes"
+	. "github.com/onsi/gomega/gexec"
+	. "github.com/onsi/gomega/ghttp"
+)
+
+var _ = Describe("Target", func() {
+	BeforeEach(func() {
+		SetupServers(server, authServer)
+	})
+
+	Describe("add", func() {
+		It("adds a profile targeting the provided server", func() {
+			session := runCommand("target", "add", "staging", "-s", server.URL(), "--ca-cert", "../test/server-tls-ca.pem", "--ca-cert", "../test/auth-tls-ca.pem")
+
+			Eventually(session).Should(Exit(0))
+			Eventually(session.Out).Should(Say("Added profile 'staging'"))
+
+			profiles, _ := config.ReadProfiles()
+			Expect(profiles.Profiles).To(HaveKey("staging"))
+			Expect(profiles.Profiles["staging"].ApiURL).To(Equal(server.URL()))
+			Expect(profiles.Profiles["staging"].AuthURL).To(Equal(authServer.URL()))
+			Expect(profiles.Profiles["staging"].CaCerts).To(HaveLen(2))
+		})
+
+		It("does not change the active profile", func() {
+			session := runCommand("target", "add", "staging")
+
+			Eventually(session).Should(Exit(0))
+			profiles, _ := config.ReadProfiles()
+			Expect(profiles.ActiveProfile()).To(Equal(config.DefaultProfile))
+		})
+
+		It("errors when the profile already exists", func() {
+			session := runCommand("target", "add", "default")
+
+			Eventually(session).Should(Exit(2))
+			Expect(session.Err).To(Say("The profile 'default' already exists."))
+		})
+	})
+
+	Describe("list", func() {
+		It("lists profiles and marks the active one", func() {
+			Eventually(runCommand("target", "add", "staging")).Should(Exit(0))
+
+			session := runCommand("target", "list")
+
+			Eventually(session).Should(Exit(0))
+			Expect(session.Out).To(Say(`\* default\s+` + server.URL()))
+			Expect(session.Out).To(Say(`  staging\s+\(no API target set\)`))
+		})
+	})
+
+	Describe("use", func() {
+		It("switches the active profile", func() {
+			Eventually(runCommand("target", "add", "staging")).Should(Exit(0))
+
+			session := runCommand("target", "use", "staging")
+
+			Eventually(session).Should(Exit(0))
+			Eventually(session.Out).Should(Say("Using profile 'staging'"))
+			profiles, _ := config.ReadProfiles()
+			Expect(profiles.CurrentProfile).To(Equal("staging"))
+
+			session = runCommand("api")
+			Eventually(session).Should(Exit(3))
+			Expect(session.Err).To(Say("An API target is not set."))
+		})
+
+		It("errors when the profile does not exist", func() {
+			session := runCommand("target", "use", "missing")
+
+			Eventually(session).Should(Exit(5))
+			Expect(session.Err).To(Say("The profile 'missing' does not exist."))
+		})
+	})
+
+	Describe("remove", func() {
+		It("removes the profile and falls back to the default profile", func() {
+			Eventually(runCommand("target", "add", "staging")).Should(Exit(0))
+			Eventually(runCommand("target", "use", "staging")).Should(Exit(0))
+
+			session := runCommand("target", "remove", "staging")
+
+			Eventually(session).Should(Exit(0))
+			Eventually(session.Out).Should(Say("Removed profile 'staging'"))
+
+			profiles, _ := config.ReadProfiles()
+			Expect(profiles.Profiles).NotTo(HaveKey("staging"))
+			Expect(profiles.ActiveProfile()).To(Equal(config.DefaultProfile))
+		})
+	})
+
+	Describe("selecting a profile", func() {
+		var otherServer *Server
+
+		BeforeEach(func() {
+			otherServer = NewTlsServer("../test/server-tls-cert.pem", "../test/server-tls-key.pem")
+			SetupServers(otherServer, authServer)
+
+			Eventually(runCommand("target", "add", "other", "-s", otherServer.URL(), "--ca-cert", "../test/server-tls-ca.pem", "--ca-cert", "../test/auth-tls-ca.pem")).Should(Exit(0))
+		})
+
+		AfterEach(func() {
+			otherServer.Close()
+		})
+
+		It("uses the profile given with --profile", func() {
+			session := runCommand("--profile", "other", "api")
+
+			Eventually(session).Should(Exit(0))
+			Eventually(session.Out).Should(Say(otherServer.URL()))
+		})
+
+		It("uses the profile given with CREDHUB_PROFILE", func() {
+			session := runCommandWithEnv([]string{"CREDHUB_PROFILE=other"}, "api")
+
+			Eventually(session).Should(Exit(0))
+			Eventually(session.Out).Should(Say(otherServer.URL()))
+		})
+
+		It("errors when the profile given does not exist", func() {
+			session := runCommandWithEnv([]string{"CREDHUB_PROFILE=missing"}, "get", "-n", "test-credential")
+
+			Eventually(session).Should(Exit(5))
+			Expect(session.Err).To(Say("The profile 'missing' does not exist."))
+
+			profiles, _ := config.ReadProfiles()
+			Expect(profiles.Profiles).NotTo(HaveKey("missing"))
+		})
+
+		It("creates the profile given when targeting a server", func() {
+			session := runCommand("--profile", "new", "api", "-s", otherServer.URL(), "--ca-cert", "../test/server-tls-ca.pem", "--ca-cert", "../test/auth-tls-ca.pem")
+
+			Eventually(session).Should(Exit(0))
+
+			profiles, _ := config.ReadProfiles()
+			Expect(profiles.Profiles["new"].ApiURL).To(Equal(otherServer.URL()))
+		})
+
+		It("keeps separate tokens for each profile", func() {
+			authServer.AppendHandlers(
+				CombineHandlers(
+					VerifyRequest("POST", "/oauth/token"),
+					RespondWith(http.StatusOK, `{
+					"access_token":"other-access-token",
+					"refresh_token":"other-refresh-token",
+					"token_type":"password",
+					"expires_in":123456789
+					}`),
+				),
+			)
+
+			session := runCommand("--profile", "other", "login", "-u", "test-username", "-p", "test-password")
+			Eventually(session).Should(Exit(0))
+
+			profiles, _ := config.ReadProfiles()
+			Expect(profiles.Profiles["other"].AccessToken).To(Equal("other-access-token"))
+			Expect(profiles.Profiles[config.DefaultProfile].AccessToken).NotTo(Equal("other-access-token"))
+		})
+	})
+
+	Describe("migrating a single target config", func() {
+		It("moves the existing target into the default profile", func() {
+			legacy := `{"ApiURL":"` + server.URL() + `","AuthURL":"https://uaa.example.com","AccessToken":"legacy-token"}`
+			Expect(ioutil.WriteFile(config.ConfigPath(), []byte(legacy), os.FileMode(0600))).To(Succeed())
+
+			session := runCommand("api")
+
+			Eventually(session).Should(Exit(0))
+			Eventually(session.Out).Should(Say(server.URL()))
+
+			cfg, _ := config.ReadConfig()
+			Expect(cfg.AccessToken).To(Equal("legacy-token"))
+
+			Eventually(runCommand("target", "add", "staging")).Should(Exit(0))
+
+			data, err := ioutil.ReadFile(config.ConfigPath())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(data)).To(ContainSubstring(`"Profiles":{"default":{"ApiURL":"` + server.URL() + `"`))
+		})
+	})
+})
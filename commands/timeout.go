@@ -0,0 +1,13 @@
+package commands
+
+import (
+	"time"
+
+	"github.com/cloudfoundry-incubator/credhub-cli/client"
+)
+
+func init() {
+	CredHub.Timeout = func(timeout time.Duration) {
+		client.SetTimeout(timeout)
+	}
+}
@@ -0,0 +1,43 @@
+package commands_test
+
+import (
+	"net/http"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gbytes"
+	. "github.com/onsi/gomega/gexec"
+)
+
+var _ = Describe("Timeout", func() {
+	BeforeEach(func() {
+		login()
+
+		server.RouteToHandler("GET", "/api/v1/data", func(w http.ResponseWriter, r *http.Request) {
+			time.Sleep(500 * time.Millisecond)
+			w.Write([]byte(`{"data":[]}`))
+		})
+	})
+
+	It("gives up on requests that take longer than --timeout", func() {
+		session := runCommand("--timeout", "50ms", "get", "-n", "my-value")
+
+		Eventually(session).Should(Exit(7))
+		Expect(session.Err).To(Say("Client.Timeout exceeded"))
+	})
+
+	It("reads the timeout from CREDHUB_TIMEOUT", func() {
+		session := runCommandWithEnv([]string{"CREDHUB_TIMEOUT=50ms"}, "get", "-n", "my-value")
+
+		Eventually(session).Should(Exit(7))
+		Expect(session.Err).To(Say("Client.Timeout exceeded"))
+	})
+
+	It("rejects an invalid timeout", func() {
+		session := runCommand("--timeout", "soon", "get", "-n", "my-value")
+
+		Eventually(session).Should(Exit(2))
+		Expect(session.Err).To(Say(`invalid duration "soon"`))
+	})
+})
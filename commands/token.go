@@ -10,7 +10,11 @@ import (
 
 func init() {
 	CredHub.Token = func() {
-		cfg := config.ReadConfig()
+		cfg, err := config.ReadConfig()
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err.Error())
+			os.Exit(1)
+		}
 
 		if cfg.AccessToken != "" && cfg.AccessToken != "revoked" {
 			credhubClient, _ := initializeCredhubClient(cfg)
@@ -26,7 +30,11 @@ func init() {
 			cfg.AccessToken = oauth.AccessToken()
 			cfg.RefreshToken = oauth.RefreshToken()
 
-			config.WriteConfig(cfg)
+			config.UpdateConfig(func(latest *config.Config) error {
+				latest.AccessToken = cfg.AccessToken
+				latest.RefreshToken = cfg.RefreshToken
+				return nil
+			})
 
 			fmt.Println("Bearer " + cfg.AccessToken)
 		} else {
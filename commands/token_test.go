@@ -26,7 +26,7 @@ var _ = Describe("Token", func() {
 	Context("when the config file has a token", func() {
 
 		BeforeEach(func() {
-			cfg := config.ReadConfig()
+			cfg, _ := config.ReadConfig()
 			cfg.AccessToken = "2YotnFZFEjr1zCsicMWpAA"
 			config.WriteConfig(cfg)
 
@@ -57,7 +57,7 @@ var _ = Describe("Token", func() {
 
 	Context("when the config file does not have a token", func() {
 		BeforeEach(func() {
-			cfg := config.ReadConfig()
+			cfg, _ := config.ReadConfig()
 			cfg.AccessToken = ""
 			config.WriteConfig(cfg)
 		})
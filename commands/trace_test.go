@@ -0,0 +1,59 @@
+package commands_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gbytes"
+	. "github.com/onsi/gomega/gexec"
+)
+
+var _ = Describe("Tracing", func() {
+	BeforeEach(func() {
+		login()
+
+		newCredentialStore(server, map[string]string{
+			"/my-password": valueCredential("password", "/my-password", "hunter2"),
+		})
+	})
+
+	It("traces requests to standard error when CREDHUB_TRACE is true", func() {
+		session := runCommandWithEnv([]string{"CREDHUB_TRACE=true"}, "get", "-n", "/my-password")
+
+		Eventually(session).Should(Exit(0))
+		Expect(session.Out).To(Say("value: hunter2"))
+		Expect(session.Err).To(Say(`REQUEST: \[.+\] GET .+/api/v1/data\?name=%2Fmy-password&versions=1`))
+		Expect(session.Err).To(Say(`Authorization: \[REDACTED\]`))
+		Expect(session.Err).To(Say(`RESPONSE: 200 OK`))
+		Expect(session.Err).To(Say(`"value":"\[REDACTED\]"`))
+		Expect(session.Err.Contents()).NotTo(ContainSubstring("hunter2"))
+	})
+
+	It("traces requests to the file named by CREDHUB_TRACE", func() {
+		dir, err := ioutil.TempDir("", "credhub-trace")
+		Expect(err).NotTo(HaveOccurred())
+		defer os.RemoveAll(dir)
+		traceFile := filepath.Join(dir, "trace.log")
+
+		session := runCommandWithEnv([]string{"CREDHUB_TRACE=" + traceFile}, "get", "-n", "/my-password")
+
+		Eventually(session).Should(Exit(0))
+		Expect(session.Err.Contents()).To(BeEmpty())
+
+		trace, err := ioutil.ReadFile(traceFile)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(trace)).To(ContainSubstring("GET "))
+		Expect(string(trace)).To(ContainSubstring("RESPONSE: 200 OK"))
+		Expect(string(trace)).NotTo(ContainSubstring("hunter2"))
+	})
+
+	It("does not trace when CREDHUB_TRACE is false", func() {
+		session := runCommandWithEnv([]string{"CREDHUB_TRACE=false"}, "get", "-n", "/my-password")
+
+		Eventually(session).Should(Exit(0))
+		Expect(session.Err.Contents()).To(BeEmpty())
+	})
+})
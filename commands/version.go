@@ -6,11 +6,31 @@ import (
 	"os"
 
 	"github.com/cloudfoundry-incubator/credhub-cli/config"
+	"github.com/cloudfoundry-incubator/credhub-cli/credhub"
 	"github.com/cloudfoundry-incubator/credhub-cli/version"
 )
 
+type VersionCommand struct {
+	Capabilities bool `long:"capabilities" description:"List the API features supported by the targeted server"`
+}
+
+func (cmd VersionCommand) Execute([]string) error {
+	if err := PrintVersion(); err != nil {
+		return err
+	}
+
+	if cmd.Capabilities {
+		return PrintCapabilities()
+	}
+
+	return nil
+}
+
 func PrintVersion() error {
-	cfg := config.ReadConfig()
+	cfg, err := config.ReadConfig()
+	if err != nil {
+		return err
+	}
 
 	credHubServerVersion := "Not Found"
 	credhubInfo, err := GetApiInfo(cfg.ApiURL, cfg.CaCerts, cfg.InsecureSkipVerify)
@@ -24,9 +44,40 @@ func PrintVersion() error {
 	return nil
 }
 
+func PrintCapabilities() error {
+	cfg, err := config.ReadConfig()
+	if err != nil {
+		return err
+	}
+
+	credhubClient, err := credhub.New(cfg.ApiURL, credhub.CaCerts(cfg.CaCerts...), credhub.SkipTLSValidation(cfg.InsecureSkipVerify), transportOptions())
+	if err != nil {
+		return err
+	}
+
+	capabilities, err := credhubClient.Capabilities()
+	if err != nil {
+		fmt.Println("Capabilities: Not Found")
+		return nil
+	}
+
+	fmt.Println("Capabilities:")
+	fmt.Println("  regenerate-endpoint:", capabilities.RegenerateEndpoint)
+	fmt.Println("  versions-query:", capabilities.VersionsQuery)
+	fmt.Println("  interpolate:", capabilities.Interpolate)
+	fmt.Println("  permissions:", capabilities.Permissions)
+	fmt.Println("  mode:", capabilities.Mode)
+
+	return nil
+}
+
 func init() {
 	CredHub.Version = func() {
-		PrintVersion()
+		err := PrintVersion()
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err.Error())
+			os.Exit(1)
+		}
 		os.Exit(0)
 	}
 }
@@ -5,6 +5,7 @@ import (
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gbytes"
 	. "github.com/onsi/gomega/gexec"
 	. "github.com/onsi/gomega/ghttp"
 )
@@ -27,6 +28,51 @@ var _ = Describe("Version", func() {
 			testVersion(sout)
 			Expect(sout).To(ContainSubstring("Server Version: 0.2.0"))
 		})
+
+		It("displays the version with the version command", func() {
+			session := runCommand("version")
+
+			Eventually(session).Should(Exit(0))
+			sout := string(session.Out.Contents())
+			testVersion(sout)
+			Expect(sout).To(ContainSubstring("Server Version: 0.2.0"))
+			Expect(sout).NotTo(ContainSubstring("Capabilities"))
+		})
+
+		It("lists the capabilities of the server", func() {
+			server.RouteToHandler("GET", "/info",
+				RespondWith(http.StatusOK, `{"app":{"name":"CredHub","version":"1.4.0"}}`),
+			)
+
+			session := runCommand("version", "--capabilities")
+
+			Eventually(session).Should(Exit(0))
+			Expect(session.Out).To(Say("Server Version: 1.4.0"))
+			Expect(session.Out).To(Say(`Capabilities:
+  regenerate-endpoint: true
+  versions-query: true
+  interpolate: true
+  permissions: true
+  mode: false
+`))
+		})
+
+		It("lists the features reported by the server over its version", func() {
+			server.RouteToHandler("GET", "/info",
+				RespondWith(http.StatusOK, `{"app":{"name":"CredHub","version":"1.4.0"},"features":{"interpolate":false,"mode":true}}`),
+			)
+
+			session := runCommand("version", "--capabilities")
+
+			Eventually(session).Should(Exit(0))
+			Expect(session.Out).To(Say(`Capabilities:
+  regenerate-endpoint: true
+  versions-query: true
+  interpolate: false
+  permissions: true
+  mode: true
+`))
+		})
 	})
 
 	Context("when the request fails", func() {
@@ -44,6 +90,13 @@ var _ = Describe("Version", func() {
 			testVersion(sout)
 			Expect(sout).To(ContainSubstring("Server Version: Not Found"))
 		})
+
+		It("reports that the capabilities are unknown", func() {
+			session := runCommand("version", "--capabilities")
+
+			Eventually(session).Should(Exit(0))
+			Expect(session.Out).To(Say("Capabilities: Not Found"))
+		})
 	})
 
 })
@@ -5,7 +5,9 @@ import (
 	"io/ioutil"
 	"os"
 	"path"
+	"reflect"
 
+	"github.com/cloudfoundry-incubator/credhub-cli/errors"
 	"github.com/cloudfoundry-incubator/credhub-cli/util"
 )
 
@@ -22,40 +24,236 @@ type Config struct {
 	ServerVersion      string
 }
 
+// ConfigDir is the directory holding config.json. CREDHUB_CONFIG_DIR takes
+// precedence; otherwise ~/.credhub is used, unless it does not exist and
+// XDG_CONFIG_HOME is set, in which case $XDG_CONFIG_HOME/credhub is used.
 func ConfigDir() string {
-	return path.Join(userHomeDir(), ".credhub")
+	if dir := os.Getenv("CREDHUB_CONFIG_DIR"); dir != "" {
+		return dir
+	}
+
+	homeConfigDir := path.Join(userHomeDir(), ".credhub")
+
+	if xdgConfigHome := os.Getenv("XDG_CONFIG_HOME"); xdgConfigHome != "" {
+		if _, err := os.Stat(homeConfigDir); os.IsNotExist(err) {
+			return path.Join(xdgConfigHome, "credhub")
+		}
+	}
+
+	return homeConfigDir
+}
+
+// UserHomeDir returns the home directory of the current user.
+func UserHomeDir() string {
+	return userHomeDir()
 }
 
 func ConfigPath() string {
 	return path.Join(ConfigDir(), "config.json")
 }
 
-func ReadConfig() Config {
-	c := Config{}
+func lockPath() string {
+	return path.Join(ConfigDir(), "config.json.lock")
+}
+
+// ReadConfig returns the configuration of the active profile. A profile
+// selected with SetProfile or CREDHUB_PROFILE must exist.
+func ReadConfig() (Config, error) {
+	return readConfig(false)
+}
 
-	data, err := ioutil.ReadFile(ConfigPath())
+// ReadOrNewConfig is like ReadConfig, but returns an empty configuration for a
+// selected profile that does not exist yet, for commands that create it.
+func ReadOrNewConfig() (Config, error) {
+	return readConfig(true)
+}
+
+func readConfig(allowNew bool) (Config, error) {
+	profiles, err := ReadProfiles()
+	if err != nil {
+		return Config{}, err
+	}
+
+	name := profiles.ActiveProfile()
+	cfg, ok := profiles.Profiles[name]
+	if !ok && !allowNew && profileSelected() {
+		return Config{}, errors.NewProfileNotFoundError(name)
+	}
+
+	if profiles.CredentialHelper != "" {
+		err = profiles.CredentialHelper.Get(name, &cfg)
+	}
+
+	return cfg, err
+}
+
+// ReadProfileConfig returns the configuration of the named profile, which
+// need not be the active one.
+func ReadProfileConfig(name string) (Config, error) {
+	profiles, err := ReadProfiles()
 	if err != nil {
-		return c
+		return Config{}, err
 	}
 
-	json.Unmarshal(data, &c)
+	cfg, ok := profiles.Profiles[name]
+	if !ok {
+		return Config{}, errors.NewProfileNotFoundError(name)
+	}
+
+	if profiles.CredentialHelper != "" {
+		err = profiles.CredentialHelper.Get(name, &cfg)
+	}
 
-	return c
+	return cfg, err
 }
 
+// WriteConfig stores c as the active profile, replacing every field of it.
+// Other profiles are re-read under the config lock so concurrent writers do
+// not clobber each other. Use UpdateConfig to change only some fields of a
+// profile that was read before a slow operation such as a token refresh.
 func WriteConfig(c Config) error {
+	return UpdateProfiles(func(profiles *Profiles) error {
+		return storeProfile(profiles, profiles.ActiveProfile(), c)
+	})
+}
+
+// UpdateConfig re-reads the active profile and applies update to it while
+// holding the config lock, so that changes made by concurrent invocations
+// since the profile was last read are kept.
+func UpdateConfig(update func(*Config) error) error {
+	return UpdateProfiles(func(profiles *Profiles) error {
+		name := profiles.ActiveProfile()
+		cfg := profiles.Profiles[name]
+
+		if profiles.CredentialHelper != "" {
+			if err := profiles.CredentialHelper.Get(name, &cfg); err != nil {
+				return err
+			}
+		}
+
+		if err := update(&cfg); err != nil {
+			return err
+		}
+
+		return storeProfile(profiles, name, cfg)
+	})
+}
+
+func storeProfile(profiles *Profiles, name string, c Config) error {
+	if profiles.CredentialHelper != "" {
+		if err := profiles.CredentialHelper.Store(name, c); err != nil {
+			return err
+		}
+		c.AccessToken = ""
+		c.RefreshToken = ""
+	}
+
+	profiles.Profiles[name] = c
+	return nil
+}
+
+// ReadProfiles returns every profile in the config file. A missing file
+// yields no profiles; a file that cannot be parsed is reported as an error.
+func ReadProfiles() (Profiles, error) {
+	p := Profiles{Profiles: map[string]Config{}}
+
+	data, err := ioutil.ReadFile(ConfigPath())
+	if os.IsNotExist(err) {
+		return p, nil
+	}
+	if err != nil {
+		return p, err
+	}
+
+	if err := json.Unmarshal(data, &p); err != nil {
+		return Profiles{Profiles: map[string]Config{}}, errors.NewCorruptConfigError(ConfigPath(), err)
+	}
+
+	if len(p.Profiles) == 0 {
+		p.Profiles = map[string]Config{}
+
+		legacy := Config{}
+		json.Unmarshal(data, &legacy)
+		if !reflect.DeepEqual(legacy, Config{}) {
+			p.Profiles[DefaultProfile] = legacy
+		}
+	}
+
+	return p, nil
+}
+
+// UpdateProfiles performs a read-modify-write of the config file while
+// holding an advisory lock, so that parallel CLI invocations serialize.
+func UpdateProfiles(update func(*Profiles) error) error {
 	err := makeDirectory()
 	if err != nil {
 		return err
 	}
 
-	data, err := json.Marshal(c)
+	lock, err := os.OpenFile(lockPath(), os.O_RDWR|os.O_CREATE, 0600)
 	if err != nil {
 		return err
 	}
+	defer lock.Close()
+
+	if err := lockFile(lock); err != nil {
+		return err
+	}
+	defer unlockFile(lock)
+
+	profiles, err := ReadProfiles()
+	if err != nil {
+		return err
+	}
+
+	if err := update(&profiles); err != nil {
+		return err
+	}
+
+	return writeProfiles(profiles)
+}
+
+func WriteProfiles(p Profiles) error {
+	return UpdateProfiles(func(profiles *Profiles) error {
+		*profiles = p
+		return nil
+	})
+}
+
+// writeProfiles replaces config.json atomically by writing to a temporary
+// file in the same directory and renaming it over the original.
+func writeProfiles(p Profiles) error {
+	data, err := json.Marshal(p)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := ioutil.TempFile(ConfigDir(), "config.json.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if err := tmp.Chmod(0600); err != nil {
+		tmp.Close()
+		return err
+	}
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+
+	if err := tmp.Close(); err != nil {
+		return err
+	}
 
-	configPath := ConfigPath()
-	return ioutil.WriteFile(configPath, data, 0600)
+	return os.Rename(tmp.Name(), ConfigPath())
 }
 
 func RemoveConfig() error {
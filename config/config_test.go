@@ -3,7 +3,11 @@
 package config_test
 
 import (
+	"fmt"
 	"io/ioutil"
+	"os"
+	"path"
+	"sync"
 
 	"github.com/cloudfoundry-incubator/credhub-cli/config"
 	. "github.com/onsi/ginkgo"
@@ -91,4 +95,216 @@ var _ = Describe("Config", func() {
 			Expect(cfg.CaCerts).To(HaveLen(0))
 		})
 	})
+
+	Describe("profiles", func() {
+		var homeDir string
+
+		BeforeEach(func() {
+			var err error
+			homeDir, err = ioutil.TempDir("", "config-test")
+			Expect(err).To(BeNil())
+			os.Setenv("HOME", homeDir)
+		})
+
+		AfterEach(func() {
+			config.SetProfile("")
+			os.Unsetenv("CREDHUB_PROFILE")
+			os.RemoveAll(homeDir)
+		})
+
+		It("reads a single target config as the default profile", func() {
+			err := os.MkdirAll(config.ConfigDir(), 0755)
+			Expect(err).To(BeNil())
+			err = ioutil.WriteFile(config.ConfigPath(), []byte(`{"ApiURL":"http://api.example.com","AccessToken":"some-token"}`), 0600)
+			Expect(err).To(BeNil())
+
+			profiles, err := config.ReadProfiles()
+
+			Expect(err).To(BeNil())
+			Expect(profiles.Profiles).To(HaveLen(1))
+			Expect(profiles.Profiles[config.DefaultProfile].ApiURL).To(Equal("http://api.example.com"))
+			Expect(profiles.Profiles[config.DefaultProfile].AccessToken).To(Equal("some-token"))
+
+			cfg, err := config.ReadConfig()
+			Expect(err).To(BeNil())
+			Expect(cfg.ApiURL).To(Equal("http://api.example.com"))
+		})
+
+		It("writes the config to the active profile only", func() {
+			err := config.WriteProfiles(config.Profiles{
+				CurrentProfile: "staging",
+				Profiles: map[string]config.Config{
+					"staging": {ApiURL: "http://staging.example.com"},
+					"prod":    {ApiURL: "http://prod.example.com"},
+				},
+			})
+			Expect(err).To(BeNil())
+
+			err = config.WriteConfig(config.Config{ApiURL: "http://new-staging.example.com"})
+			Expect(err).To(BeNil())
+
+			profiles, _ := config.ReadProfiles()
+			Expect(profiles.Profiles["staging"].ApiURL).To(Equal("http://new-staging.example.com"))
+			Expect(profiles.Profiles["prod"].ApiURL).To(Equal("http://prod.example.com"))
+		})
+
+		It("reads a named profile regardless of the active profile", func() {
+			err := config.WriteProfiles(config.Profiles{
+				CurrentProfile: "staging",
+				Profiles: map[string]config.Config{
+					"staging": {ApiURL: "http://staging.example.com"},
+					"prod":    {ApiURL: "http://prod.example.com"},
+				},
+			})
+			Expect(err).To(BeNil())
+
+			cfg, err := config.ReadProfileConfig("prod")
+			Expect(err).To(BeNil())
+			Expect(cfg.ApiURL).To(Equal("http://prod.example.com"))
+
+			_, err = config.ReadProfileConfig("missing")
+			Expect(err).To(MatchError(ContainSubstring("The profile 'missing' does not exist.")))
+		})
+
+		It("errors when the selected profile does not exist unless it is being created", func() {
+			Expect(config.WriteConfig(config.Config{ApiURL: "http://api.example.com"})).To(Succeed())
+
+			config.SetProfile("missing")
+
+			_, err := config.ReadConfig()
+			Expect(err).To(MatchError(ContainSubstring("The profile 'missing' does not exist.")))
+
+			cfg, err := config.ReadOrNewConfig()
+			Expect(err).To(BeNil())
+			Expect(cfg).To(Equal(config.Config{}))
+		})
+
+		It("reads an empty config when no profile is selected and none exists", func() {
+			cfg, err := config.ReadConfig()
+
+			Expect(err).To(BeNil())
+			Expect(cfg).To(Equal(config.Config{}))
+		})
+
+		It("prefers SetProfile over CREDHUB_PROFILE over the current profile", func() {
+			profiles := config.Profiles{CurrentProfile: "from-file"}
+			Expect(profiles.ActiveProfile()).To(Equal("from-file"))
+
+			os.Setenv("CREDHUB_PROFILE", "from-env")
+			Expect(profiles.ActiveProfile()).To(Equal("from-env"))
+
+			config.SetProfile("from-flag")
+			Expect(profiles.ActiveProfile()).To(Equal("from-flag"))
+		})
+
+		It("uses the default profile when none is selected", func() {
+			Expect(config.Profiles{}.ActiveProfile()).To(Equal(config.DefaultProfile))
+		})
+	})
+
+	Describe("storage", func() {
+		var homeDir string
+
+		BeforeEach(func() {
+			var err error
+			homeDir, err = ioutil.TempDir("", "config-test")
+			Expect(err).To(BeNil())
+			os.Setenv("HOME", homeDir)
+		})
+
+		AfterEach(func() {
+			os.Unsetenv("CREDHUB_CONFIG_DIR")
+			os.Unsetenv("XDG_CONFIG_HOME")
+			os.RemoveAll(homeDir)
+		})
+
+		It("uses CREDHUB_CONFIG_DIR when it is set", func() {
+			os.Setenv("CREDHUB_CONFIG_DIR", path.Join(homeDir, "custom"))
+
+			Expect(config.ConfigPath()).To(Equal(path.Join(homeDir, "custom", "config.json")))
+		})
+
+		It("uses XDG_CONFIG_HOME when ~/.credhub does not exist", func() {
+			os.Setenv("XDG_CONFIG_HOME", path.Join(homeDir, "xdg"))
+
+			Expect(config.ConfigPath()).To(Equal(path.Join(homeDir, "xdg", "credhub", "config.json")))
+		})
+
+		It("keeps using ~/.credhub when it already exists", func() {
+			Expect(os.MkdirAll(path.Join(homeDir, ".credhub"), 0755)).To(Succeed())
+			os.Setenv("XDG_CONFIG_HOME", path.Join(homeDir, "xdg"))
+
+			Expect(config.ConfigPath()).To(Equal(path.Join(homeDir, ".credhub", "config.json")))
+		})
+
+		It("reports a config file that cannot be parsed", func() {
+			Expect(os.MkdirAll(config.ConfigDir(), 0755)).To(Succeed())
+			Expect(ioutil.WriteFile(config.ConfigPath(), []byte(`{"ApiURL":`), 0600)).To(Succeed())
+
+			_, err := config.ReadConfig()
+			Expect(err).To(MatchError(ContainSubstring("The config file at " + config.ConfigPath() + " could not be parsed")))
+
+			err = config.WriteConfig(config.Config{ApiURL: "http://api.example.com"})
+			Expect(err).To(HaveOccurred())
+
+			data, _ := ioutil.ReadFile(config.ConfigPath())
+			Expect(string(data)).To(Equal(`{"ApiURL":`))
+		})
+
+		It("does not leave temporary files behind", func() {
+			Expect(config.WriteConfig(config.Config{ApiURL: "http://api.example.com"})).To(Succeed())
+
+			files, err := ioutil.ReadDir(config.ConfigDir())
+			Expect(err).To(BeNil())
+
+			names := []string{}
+			for _, f := range files {
+				names = append(names, f.Name())
+			}
+			Expect(names).To(ConsistOf("config.json", "config.json.lock"))
+		})
+
+		It("applies updates to the latest copy of the active profile", func() {
+			Expect(config.WriteConfig(config.Config{ApiURL: "http://api.example.com", AccessToken: "old-token"})).To(Succeed())
+
+			stale, err := config.ReadConfig()
+			Expect(err).To(BeNil())
+
+			Expect(config.WriteConfig(config.Config{ApiURL: "http://new-api.example.com", AccessToken: "old-token"})).To(Succeed())
+
+			err = config.UpdateConfig(func(cfg *config.Config) error {
+				Expect(cfg.ApiURL).NotTo(Equal(stale.ApiURL))
+				cfg.AccessToken = "new-token"
+				return nil
+			})
+			Expect(err).To(BeNil())
+
+			cfg, err := config.ReadConfig()
+			Expect(err).To(BeNil())
+			Expect(cfg).To(Equal(config.Config{ApiURL: "http://new-api.example.com", AccessToken: "new-token"}))
+		})
+
+		It("serializes concurrent updates", func() {
+			var wg sync.WaitGroup
+
+			for i := 0; i < 20; i++ {
+				wg.Add(1)
+				go func(i int) {
+					defer GinkgoRecover()
+					defer wg.Done()
+
+					err := config.UpdateProfiles(func(p *config.Profiles) error {
+						p.Profiles[fmt.Sprintf("profile-%d", i)] = config.Config{}
+						return nil
+					})
+					Expect(err).To(BeNil())
+				}(i)
+			}
+			wg.Wait()
+
+			profiles, err := config.ReadProfiles()
+			Expect(err).To(BeNil())
+			Expect(profiles.Profiles).To(HaveLen(20))
+		})
+	})
 })
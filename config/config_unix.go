@@ -4,6 +4,7 @@ package config
 
 import (
 	"os"
+	"syscall"
 )
 
 func userHomeDir() string {
@@ -13,3 +14,11 @@ func userHomeDir() string {
 func makeDirectory() error {
 	return os.MkdirAll(ConfigDir(), 0755)
 }
+
+func lockFile(f *os.File) error {
+	return syscall.Flock(int(f.Fd()), syscall.LOCK_EX)
+}
+
+func unlockFile(f *os.File) error {
+	return syscall.Flock(int(f.Fd()), syscall.LOCK_UN)
+}
@@ -0,0 +1,102 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"os/exec"
+	"strings"
+
+	"github.com/cloudfoundry-incubator/credhub-cli/errors"
+)
+
+const credentialHelperPrefix = "credhub-credential-"
+
+// CredentialHelper names an external program, credhub-credential-<name>, that
+// stores the access and refresh tokens of each profile in place of the config
+// file.
+//
+// The program is invoked with a single action argument and exchanges JSON
+// over stdin and stdout:
+//
+//	get    reads {"Profile","ServerURL"} and writes {"AccessToken","RefreshToken"},
+//	       or writes nothing when no tokens are stored
+//	store  reads {"Profile","ServerURL","AccessToken","RefreshToken"}
+//	erase  reads {"Profile","ServerURL"}
+//
+// A non-zero exit status is reported as an error, with stderr as its message.
+type CredentialHelper string
+
+// HelperTokens is the JSON document exchanged with a CredentialHelper.
+type HelperTokens struct {
+	Profile      string
+	ServerURL    string
+	AccessToken  string `json:",omitempty"`
+	RefreshToken string `json:",omitempty"`
+}
+
+func (h CredentialHelper) Program() string {
+	return credentialHelperPrefix + string(h)
+}
+
+// Get fills the tokens of cfg from the helper. Tokens already in cfg are kept
+// when the helper has none stored for the profile.
+func (h CredentialHelper) Get(profile string, cfg *Config) error {
+	var tokens HelperTokens
+
+	err := h.run("get", HelperTokens{Profile: profile, ServerURL: cfg.ApiURL}, &tokens)
+	if err != nil {
+		return err
+	}
+
+	if tokens.AccessToken != "" || tokens.RefreshToken != "" {
+		cfg.AccessToken = tokens.AccessToken
+		cfg.RefreshToken = tokens.RefreshToken
+	}
+
+	return nil
+}
+
+func (h CredentialHelper) Store(profile string, cfg Config) error {
+	return h.run("store", HelperTokens{
+		Profile:      profile,
+		ServerURL:    cfg.ApiURL,
+		AccessToken:  cfg.AccessToken,
+		RefreshToken: cfg.RefreshToken,
+	}, nil)
+}
+
+func (h CredentialHelper) Erase(profile string, cfg Config) error {
+	return h.run("erase", HelperTokens{Profile: profile, ServerURL: cfg.ApiURL}, nil)
+}
+
+func (h CredentialHelper) run(action string, input interface{}, output interface{}) error {
+	data, err := json.Marshal(input)
+	if err != nil {
+		return err
+	}
+
+	var stdout, stderr bytes.Buffer
+
+	cmd := exec.Command(h.Program(), action)
+	cmd.Stdin = bytes.NewReader(data)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		message := strings.TrimSpace(stderr.String())
+		if message == "" {
+			message = err.Error()
+		}
+		return errors.NewCredentialHelperError(h.Program(), action, message)
+	}
+
+	if output == nil || len(bytes.TrimSpace(stdout.Bytes())) == 0 {
+		return nil
+	}
+
+	if err := json.Unmarshal(stdout.Bytes(), output); err != nil {
+		return errors.NewCredentialHelperError(h.Program(), action, err.Error())
+	}
+
+	return nil
+}
@@ -0,0 +1,109 @@
+package config_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/cloudfoundry-incubator/credhub-cli/config"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("CredentialHelper", func() {
+	var (
+		homeDir   string
+		storeFile string
+		oldPath   string
+	)
+
+	BeforeEach(func() {
+		var err error
+		homeDir, err = ioutil.TempDir("", "config-test")
+		Expect(err).NotTo(HaveOccurred())
+		os.Setenv("HOME", homeDir)
+		os.Setenv("USERPROFILE", homeDir)
+
+		storeFile = filepath.Join(homeDir, "stub-store.json")
+		os.Setenv("CREDHUB_CREDENTIAL_STUB_FILE", storeFile)
+
+		oldPath = os.Getenv("PATH")
+		os.Setenv("PATH", filepath.Dir(credentialHelperStubPath)+string(os.PathListSeparator)+oldPath)
+
+		err = config.WriteProfiles(config.Profiles{
+			CredentialHelper: "stub",
+			Profiles: map[string]config.Config{
+				config.DefaultProfile: {ApiURL: "https://api.example.com"},
+			},
+		})
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		os.Setenv("PATH", oldPath)
+		os.Unsetenv("CREDHUB_CREDENTIAL_STUB_FILE")
+		os.Unsetenv("CREDHUB_CREDENTIAL_STUB_FAIL")
+		os.RemoveAll(homeDir)
+	})
+
+	It("stores tokens with the helper instead of the config file", func() {
+		err := config.WriteConfig(config.Config{
+			ApiURL:       "https://api.example.com",
+			AccessToken:  "some-access-token",
+			RefreshToken: "some-refresh-token",
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		data, err := ioutil.ReadFile(config.ConfigPath())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(data)).NotTo(ContainSubstring("some-access-token"))
+		Expect(string(data)).NotTo(ContainSubstring("some-refresh-token"))
+
+		stored := map[string]config.HelperTokens{}
+		data, err = ioutil.ReadFile(storeFile)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(json.Unmarshal(data, &stored)).To(Succeed())
+		Expect(stored[config.DefaultProfile].ServerURL).To(Equal("https://api.example.com"))
+		Expect(stored[config.DefaultProfile].AccessToken).To(Equal("some-access-token"))
+
+		cfg, err := config.ReadConfig()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cfg.AccessToken).To(Equal("some-access-token"))
+		Expect(cfg.RefreshToken).To(Equal("some-refresh-token"))
+	})
+
+	It("keeps tokens from the config file when the helper has none", func() {
+		err := config.UpdateProfiles(func(p *config.Profiles) error {
+			p.Profiles[config.DefaultProfile] = config.Config{AccessToken: "file-token"}
+			return nil
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		cfg, err := config.ReadConfig()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cfg.AccessToken).To(Equal("file-token"))
+	})
+
+	It("erases tokens when the profile is removed", func() {
+		Expect(config.WriteConfig(config.Config{AccessToken: "some-access-token"})).To(Succeed())
+
+		removed, err := config.RemoveProfile(config.DefaultProfile)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(removed.AccessToken).To(Equal("some-access-token"))
+
+		data, err := ioutil.ReadFile(storeFile)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(data)).To(Equal("{}"))
+	})
+
+	It("reports helper failures", func() {
+		os.Setenv("CREDHUB_CREDENTIAL_STUB_FAIL", "keychain locked")
+
+		_, err := config.ReadConfig()
+		Expect(err).To(MatchError("The credential helper 'credhub-credential-stub' failed to get tokens: keychain locked"))
+
+		err = config.WriteConfig(config.Config{AccessToken: "some-access-token"})
+		Expect(err).To(MatchError("The credential helper 'credhub-credential-stub' failed to store tokens: keychain locked"))
+	})
+})
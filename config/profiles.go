@@ -0,0 +1,83 @@
+package config
+
+import (
+	"os"
+
+	"github.com/cloudfoundry-incubator/credhub-cli/errors"
+)
+
+const DefaultProfile = "default"
+
+// Profiles is the on-disk representation of the CLI configuration. Each
+// profile holds the target, trusted CAs and tokens for a single CredHub.
+//
+// When CredentialHelper is set, tokens are kept by the helper rather than
+// in the config file.
+type Profiles struct {
+	CurrentProfile   string
+	CredentialHelper CredentialHelper `json:",omitempty"`
+	Profiles         map[string]Config
+}
+
+var profileOverride string
+
+// SetProfile selects the profile used by ReadConfig and WriteConfig for the
+// remainder of the process, taking precedence over CREDHUB_PROFILE and the
+// current profile stored in the config file.
+func SetProfile(name string) {
+	profileOverride = name
+}
+
+// profileSelected reports whether the active profile was chosen for this
+// invocation with SetProfile or CREDHUB_PROFILE.
+func profileSelected() bool {
+	return profileOverride != "" || os.Getenv("CREDHUB_PROFILE") != ""
+}
+
+func (p Profiles) ActiveProfile() string {
+	if profileOverride != "" {
+		return profileOverride
+	}
+
+	if name := os.Getenv("CREDHUB_PROFILE"); name != "" {
+		return name
+	}
+
+	if p.CurrentProfile != "" {
+		return p.CurrentProfile
+	}
+
+	return DefaultProfile
+}
+
+// RemoveProfile deletes the named profile, along with any tokens held for it
+// by the credential helper, and returns the removed configuration.
+func RemoveProfile(name string) (Config, error) {
+	var removed Config
+
+	err := UpdateProfiles(func(profiles *Profiles) error {
+		cfg, ok := profiles.Profiles[name]
+		if !ok {
+			return errors.NewProfileNotFoundError(name)
+		}
+
+		if profiles.CredentialHelper != "" {
+			if err := profiles.CredentialHelper.Get(name, &cfg); err != nil {
+				return err
+			}
+			if err := profiles.CredentialHelper.Erase(name, cfg); err != nil {
+				return err
+			}
+		}
+
+		removed = cfg
+		delete(profiles.Profiles, name)
+
+		if profiles.CurrentProfile == name {
+			profiles.CurrentProfile = ""
+		}
+		return nil
+	})
+
+	return removed, err
+}
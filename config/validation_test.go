@@ -1,8 +1,6 @@
 package config_test
 
 import (
-	"errors"
-
 	"github.com/cloudfoundry-incubator/credhub-cli/config"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -22,7 +20,7 @@ var _ = Describe("Config validation", func() {
 		cfg := config.Config{}
 		cfg.AccessToken = "non-revoked"
 
-		Expect(config.ValidateConfig(cfg)).To(Equal(errors.New("An API target is not set. Please target the location of your server with `credhub api --server api.example.com` to continue.")))
+		Expect(config.ValidateConfig(cfg)).To(MatchError("An API target is not set. Please target the location of your server with `credhub api --server api.example.com` to continue."))
 	})
 
 	It("requires a non-revoked token", func() {
@@ -30,14 +28,14 @@ var _ = Describe("Config validation", func() {
 		cfg.ApiURL = "http://api.example.com"
 		cfg.AccessToken = "revoked"
 
-		Expect(config.ValidateConfig(cfg)).To(Equal(errors.New("You are not currently authenticated. Please log in to continue.")))
+		Expect(config.ValidateConfig(cfg)).To(MatchError("You are not currently authenticated. Please log in to continue."))
 	})
 
 	It("requires a non-empty token", func() {
 		cfg := config.Config{}
 		cfg.ApiURL = "http://api.example.com"
 
-		Expect(config.ValidateConfig(cfg)).To(Equal(errors.New("You are not currently authenticated. Please log in to continue.")))
+		Expect(config.ValidateConfig(cfg)).To(MatchError("You are not currently authenticated. Please log in to continue."))
 
 	})
 })
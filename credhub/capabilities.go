@@ -0,0 +1,60 @@
+package credhub
+
+import (
+	version "github.com/hashicorp/go-version"
+)
+
+// Capabilities describes the API features supported by the targeted CredHub server.
+type Capabilities struct {
+	// RegenerateEndpoint is set when credentials are regenerated with /api/v1/regenerate
+	// instead of a regenerate flag sent to /api/v1/data.
+	RegenerateEndpoint bool
+	// VersionsQuery is set when the current credential is requested with versions=1
+	// instead of current=true.
+	VersionsQuery bool
+	// Interpolate is set when the server can interpolate credentials into VCAP_SERVICES.
+	Interpolate bool
+	// Permissions is set when the server manages credential permissions.
+	Permissions bool
+	// Mode is set when set and generate requests accept a mode instead of the overwrite flag.
+	Mode bool
+}
+
+// Minimum server versions that support each capability.
+const (
+	interpolateVersion        = "1.1.0"
+	permissionsVersion        = "1.3.0"
+	regenerateEndpointVersion = "1.4.0"
+	versionsQueryVersion      = "1.4.0"
+	modeVersion               = "1.6.0"
+)
+
+// Capabilities returns the features supported by the targeted CredHub server. Features
+// reported by /info are used as given; the others are based on the server version given
+// with the ServerVersion option or reported by /info.
+func (ch *CredHub) Capabilities() (Capabilities, error) {
+	rawVersion, features, err := ch.serverInfo()
+	if err != nil {
+		return Capabilities{}, err
+	}
+
+	serverVersion, err := version.NewVersion(rawVersion)
+	if err != nil {
+		return Capabilities{}, err
+	}
+
+	supports := func(feature, minimum string) bool {
+		if supported, ok := features[feature]; ok {
+			return supported
+		}
+		return !serverVersion.LessThan(version.Must(version.NewVersion(minimum)))
+	}
+
+	return Capabilities{
+		RegenerateEndpoint: supports("regenerate-endpoint", regenerateEndpointVersion),
+		VersionsQuery:      supports("versions-query", versionsQueryVersion),
+		Interpolate:        supports("interpolate", interpolateVersion),
+		Permissions:        supports("permissions", permissionsVersion),
+		Mode:               supports("mode", modeVersion),
+	}, nil
+}
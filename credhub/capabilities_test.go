@@ -0,0 +1,94 @@
+package credhub_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/cloudfoundry-incubator/credhub-cli/credhub"
+)
+
+var _ = Describe("Capabilities()", func() {
+	It("supports every feature on a current server", func() {
+		ch, _ := New("https://example.com", ServerVersion("1.6.0"))
+
+		capabilities, err := ch.Capabilities()
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(capabilities).To(Equal(Capabilities{
+			RegenerateEndpoint: true,
+			VersionsQuery:      true,
+			Interpolate:        true,
+			Permissions:        true,
+			Mode:               true,
+		}))
+	})
+
+	It("reports the features missing from older servers", func() {
+		ch, _ := New("https://example.com", ServerVersion("1.3.0"))
+
+		capabilities, err := ch.Capabilities()
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(capabilities).To(Equal(Capabilities{
+			Interpolate: true,
+			Permissions: true,
+		}))
+	})
+
+	It("reports no features before interpolation", func() {
+		ch, _ := New("https://example.com", ServerVersion("1.0.0"))
+
+		capabilities, err := ch.Capabilities()
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(capabilities).To(Equal(Capabilities{}))
+	})
+
+	It("prefers the features reported by /info over the server version", func() {
+		testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(`{"app":{"name":"CredHub","version":"1.2.0"},"features":{"permissions":true,"interpolate":false}}`))
+		}))
+		defer testServer.Close()
+
+		ch, _ := New(testServer.URL)
+
+		capabilities, err := ch.Capabilities()
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(capabilities).To(Equal(Capabilities{
+			Permissions: true,
+		}))
+	})
+
+	It("uses the version reported by /info when none is given", func() {
+		requests := 0
+		testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			Expect(r.URL.Path).To(Equal("/info"))
+			requests++
+			w.Write([]byte(`{"app":{"name":"CredHub","version":"1.4.0"}}`))
+		}))
+		defer testServer.Close()
+
+		ch, _ := New(testServer.URL)
+
+		capabilities, err := ch.Capabilities()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(capabilities.RegenerateEndpoint).To(BeTrue())
+		Expect(capabilities.Mode).To(BeFalse())
+
+		_, err = ch.Capabilities()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(requests).To(Equal(1))
+	})
+
+	It("returns an error when the server version cannot be determined", func() {
+		ch, _ := New("http://localhost:1")
+
+		_, err := ch.Capabilities()
+
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -3,10 +3,15 @@ package credhub
 import (
 	"crypto/tls"
 	"crypto/x509"
+	"net"
 	"net/http"
 	"time"
 )
 
+// DefaultTimeout is the timeout of requests to the CredHub server unless the Timeout
+// or HTTPClient options are given.
+const DefaultTimeout = time.Second * 45
+
 // Client provides an unauthenticated http.Client to the CredHub server
 func (ch *CredHub) Client() *http.Client {
 	if ch.defaultClient == nil {
@@ -17,23 +22,60 @@ func (ch *CredHub) Client() *http.Client {
 }
 
 func (ch *CredHub) client() *http.Client {
-	if ch.baseURL.Scheme == "https" {
-		return httpsClient(ch.insecureSkipVerify, ch.caCerts)
+	var client *http.Client
+
+	if ch.httpClient != nil {
+		copied := *ch.httpClient
+		client = &copied
+	} else if ch.baseURL.Scheme == "https" {
+		client = httpsClient(ch.insecureSkipVerify, ch.caCerts, ch.dial)
+	} else {
+		client = httpClient(ch.dial)
+	}
+
+	if ch.timeout != nil {
+		client.Timeout = *ch.timeout
+	}
+
+	if ch.recorder != nil {
+		client.Transport = ch.recorder.transport(client.Transport)
+	}
+
+	if ch.tracer != nil {
+		client.Transport = NewTracingTransport(ch.tracer, client.Transport)
+	}
+
+	if len(ch.middleware) > 0 {
+		transport := client.Transport
+		if transport == nil {
+			transport = http.DefaultTransport
+		}
+		for i := len(ch.middleware) - 1; i >= 0; i-- {
+			transport = ch.middleware[i](transport)
+		}
+		client.Transport = transport
 	}
 
-	return httpClient()
+	return client
 }
 
-func httpClient() *http.Client {
-	return &http.Client{
-		Timeout: time.Second * 45,
+func httpClient(dial func(network, addr string) (net.Conn, error)) *http.Client {
+	client := &http.Client{
+		Timeout: DefaultTimeout,
 	}
+
+	if dial != nil {
+		client.Transport = &http.Transport{Dial: dial}
+	}
+
+	return client
 }
 
-func httpsClient(insecureSkipVerify bool, rootCAs *x509.CertPool) *http.Client {
-	client := httpClient()
+func httpsClient(insecureSkipVerify bool, rootCAs *x509.CertPool, dial func(network, addr string) (net.Conn, error)) *http.Client {
+	client := httpClient(nil)
 
-	client.Transport = &http.Transport{
+	transport := &http.Transport{
+		Proxy: http.ProxyFromEnvironment,
 		TLSClientConfig: &tls.Config{
 			InsecureSkipVerify:       insecureSkipVerify,
 			PreferServerCipherSuites: true,
@@ -41,5 +83,13 @@ func httpsClient(insecureSkipVerify bool, rootCAs *x509.CertPool) *http.Client {
 		},
 	}
 
+	// Connections made by a dialer are not sent through an HTTP proxy as well.
+	if dial != nil {
+		transport.Proxy = nil
+		transport.Dial = dial
+	}
+
+	client.Transport = transport
+
 	return client
 }
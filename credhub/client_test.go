@@ -3,7 +3,9 @@ package credhub_test
 import (
 	"crypto/x509"
 	"io/ioutil"
+	"net"
 	"net/http"
+	"net/http/httptest"
 	"time"
 
 	. "github.com/onsi/ginkgo"
@@ -69,4 +71,114 @@ var _ = Describe("Client()", func() {
 			Expect(tlsConfig.PreferServerCipherSuites).To(BeTrue())
 		})
 	})
+
+	It("uses the proxy from the environment", func() {
+		ch, _ := New("https://example.com", ServerVersion("2.2.2"))
+
+		transport := ch.Client().Transport.(*http.Transport)
+
+		Expect(transport.Proxy).NotTo(BeNil())
+	})
+
+	Context("With Timeout", func() {
+		It("should return a http.Client with the timeout", func() {
+			ch, _ := New("https://example.com", Timeout(5*time.Second), ServerVersion("2.2.2"))
+
+			Expect(ch.Client().Timeout).To(Equal(5 * time.Second))
+		})
+	})
+
+	Context("With HTTPClient", func() {
+		It("should return a copy of the client", func() {
+			transport := &http.Transport{}
+			httpClient := &http.Client{Transport: transport, Timeout: time.Minute}
+			ch, _ := New("https://example.com", HTTPClient(httpClient), ServerVersion("2.2.2"))
+
+			client := ch.Client()
+
+			Expect(client).NotTo(BeIdenticalTo(httpClient))
+			Expect(client.Transport).To(BeIdenticalTo(transport))
+			Expect(client.Timeout).To(Equal(time.Minute))
+		})
+
+		It("applies the timeout option to the copy", func() {
+			httpClient := &http.Client{Timeout: time.Minute}
+			ch, _ := New("https://example.com", HTTPClient(httpClient), Timeout(time.Second), ServerVersion("2.2.2"))
+
+			Expect(ch.Client().Timeout).To(Equal(time.Second))
+			Expect(httpClient.Timeout).To(Equal(time.Minute))
+		})
+
+		It("rejects a nil client", func() {
+			_, err := New("https://example.com", HTTPClient(nil))
+
+			Expect(err).To(MatchError("http client must not be nil"))
+		})
+	})
+
+	Context("With Dialer", func() {
+		It("opens connections with the dialer instead of an HTTP proxy", func() {
+			testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Write([]byte(`{"app":{"name":"CredHub","version":"1.6.0"}}`))
+			}))
+			defer testServer.Close()
+
+			var dialed []string
+			dial := func(network, addr string) (net.Conn, error) {
+				dialed = append(dialed, addr)
+				return net.Dial(network, testServer.Listener.Addr().String())
+			}
+
+			ch, _ := New("http://credhub.example.com:8844", Dialer(dial))
+
+			_, err := ch.Info()
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(dialed).To(Equal([]string{"credhub.example.com:8844"}))
+		})
+
+		It("does not use the HTTP proxy from the environment for https", func() {
+			ch, _ := New("https://example.com", Dialer(net.Dial), ServerVersion("2.2.2"))
+
+			transport := ch.Client().Transport.(*http.Transport)
+
+			Expect(transport.Proxy).To(BeNil())
+			Expect(transport.Dial).NotTo(BeNil())
+		})
+	})
+
+	Context("With Middleware", func() {
+		It("wraps the transport of every request, first middleware outermost", func() {
+			var calls []string
+			middleware := func(name string) func(http.RoundTripper) http.RoundTripper {
+				return func(next http.RoundTripper) http.RoundTripper {
+					return roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+						calls = append(calls, name)
+						r.Header.Set("X-"+name, "true")
+						return next.RoundTrip(r)
+					})
+				}
+			}
+
+			testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				Expect(r.Header.Get("X-outer")).To(Equal("true"))
+				Expect(r.Header.Get("X-inner")).To(Equal("true"))
+				w.Write([]byte(`{"app":{"name":"CredHub","version":"1.6.0"}}`))
+			}))
+			defer testServer.Close()
+
+			ch, _ := New(testServer.URL, Middleware(middleware("outer")), Middleware(middleware("inner")))
+
+			_, err := ch.Info()
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(calls).To(Equal([]string{"outer", "inner"}))
+		})
+	})
 })
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
@@ -8,8 +8,13 @@ Server HTTP API specification can be found at http://credhub-api.cfapps.io
 package credhub
 
 import (
+	"context"
+	"io"
+	"net"
 	"net/http"
 	"net/url"
+	"sync"
+	"time"
 
 	"crypto/x509"
 
@@ -43,4 +48,34 @@ type CredHub struct {
 
 	// Version of the server to make API requests against. Some methods will hit alternate endpoints based on this value
 	cachedServerVersion string
+	// Features reported by /info along with the server version, if any
+	cachedFeatures map[string]bool
+	// Guards the cached server version and features, which are fetched on first use
+	serverInfoLock *sync.Mutex
+
+	// Base client and request timeout given with the HTTPClient and Timeout options
+	httpClient *http.Client
+	timeout    *time.Duration
+
+	// Connects to the CredHub and auth servers instead of net.Dial, given with the Dialer option
+	dial func(network, addr string) (net.Conn, error)
+
+	// Records or replays every request, given with the Recorder option
+	recorder *recorder
+
+	// Receives a trace of every request, given with the Tracer option
+	tracer io.Writer
+
+	// Wrappers around the transport of every request, outermost first
+	middleware []func(http.RoundTripper) http.RoundTripper
+
+	// Cancels every request when done, set on the copies made by withContext
+	ctx context.Context
+}
+
+// withContext returns a copy of ch whose requests are cancelled when ctx is done.
+func (ch *CredHub) withContext(ctx context.Context) *CredHub {
+	copied := *ch
+	copied.ctx = ctx
+	return &copied
 }
@@ -17,3 +17,22 @@ func (ch *CredHub) Delete(name string) error {
 
 	return err
 }
+
+// DeleteByPath will delete all versions of every credential within the specified path.
+// The names of the deleted credentials are returned, including those deleted before any error.
+func (ch *CredHub) DeleteByPath(path string) ([]string, error) {
+	results, err := ch.FindByPath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var deleted []string
+	for _, result := range results.Credentials {
+		if err := ch.Delete(result.Name); err != nil {
+			return deleted, err
+		}
+		deleted = append(deleted, result.Name)
+	}
+
+	return deleted, nil
+}
@@ -6,6 +6,7 @@ import (
 	"bytes"
 	"io/ioutil"
 	"net/http"
+	"net/http/httptest"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -53,4 +54,55 @@ var _ = Describe("Delete", func() {
 			Expect(err).To(MatchError("The request could not be completed because the credential does not exist or you do not have sufficient authorization."))
 		})
 	})
+
+	Context("DeleteByPath()", func() {
+		var (
+			testServer *httptest.Server
+			deleted    []string
+		)
+
+		BeforeEach(func() {
+			deleted = nil
+			testServer = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch r.Method {
+				case http.MethodGet:
+					Expect(r.URL.Query().Get("path")).To(Equal("/deployment"))
+					w.Write([]byte(`{"credentials":[{"name":"/deployment/password","version_created_at":"2017-01-01T04:07:18Z"},{"name":"/deployment/job/certificate","version_created_at":"2017-01-01T04:07:18Z"}]}`))
+				case http.MethodDelete:
+					name := r.URL.Query().Get("name")
+					if name == "/deployment/job/certificate" && len(deleted) > 0 && deleted[0] == "fail" {
+						w.WriteHeader(http.StatusForbidden)
+						w.Write([]byte(`{"error":"You do not have sufficient authorization."}`))
+						return
+					}
+					deleted = append(deleted, name)
+					w.WriteHeader(http.StatusNoContent)
+				}
+			}))
+		})
+
+		AfterEach(func() {
+			testServer.Close()
+		})
+
+		It("deletes every credential within the path", func() {
+			ch, _ := New(testServer.URL, ServerVersion("1.4.0"))
+
+			names, err := ch.DeleteByPath("/deployment")
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(names).To(Equal([]string{"/deployment/password", "/deployment/job/certificate"}))
+			Expect(deleted).To(Equal(names))
+		})
+
+		It("returns the credentials deleted before an error", func() {
+			deleted = []string{"fail"}
+			ch, _ := New(testServer.URL, ServerVersion("1.4.0"))
+
+			names, err := ch.DeleteByPath("/deployment")
+
+			Expect(err).To(MatchError("You do not have sufficient authorization."))
+			Expect(names).To(Equal([]string{"/deployment/password"}))
+		})
+	})
 })
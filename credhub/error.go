@@ -4,8 +4,22 @@ package credhub
 type Error struct {
 	Name        string `json:"error"`
 	Description string `json:"error_description"`
+	// StatusCode is the HTTP status of the response that reported the error.
+	StatusCode int `json:"-"`
 }
 
 func (e *Error) Error() string {
 	return e.Name
 }
+
+// VersionConflictError is returned by conditional writes when the current
+// version of a credential is not the expected one.
+type VersionConflictError struct {
+	Name       string
+	ExpectedId string
+	CurrentId  string
+}
+
+func (e *VersionConflictError) Error() string {
+	return "credential " + e.Name + " is at version " + e.CurrentId + ", not the expected version " + e.ExpectedId
+}
@@ -1,8 +1,10 @@
 package credhub_test
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
+	"time"
 
 	"github.com/cloudfoundry-incubator/credhub-cli/credhub"
 	"github.com/cloudfoundry-incubator/credhub-cli/credhub/auth"
@@ -91,6 +93,27 @@ func ExampleCredHub_Request() {
 	}
 }
 
+func ExampleCredHub_Watch() {
+	_ = func() {
+		ch, _ := credhub.New("https://example.com",
+			credhub.Auth(auth.UaaClientCredentials("client-id", "client-secret")))
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		// Check for a rotated database password every 30 seconds
+		for change := range ch.Watch(ctx, []string{"/my-app/db-password"}, 30*time.Second) {
+			if change.Err != nil {
+				fmt.Println("Could not fetch", change.Name, change.Err)
+				continue
+			}
+			fmt.Println("New version of", change.Name, change.Id)
+			// Sample Output:
+			// New version of /my-app/db-password 67fc3def-bbfb-4953-83f8-4ab0682ad675
+		}
+	}
+}
+
 func Example() {
 	_ = func() {
 		// CredHub server at https://example.com, using UAA Password grant
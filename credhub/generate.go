@@ -11,51 +11,67 @@ import (
 // GeneratePassword generates a password credential based on the provided parameters.
 func (ch *CredHub) GeneratePassword(name string, gen generate.Password, overwrite bool) (credentials.Password, error) {
 	var cred credentials.Password
-	err := ch.generateCredential(name, "password", gen, overwrite, &cred)
+	err := ch.generateCredential(name, "password", gen, overwriteFields(overwrite), &cred)
 	return cred, err
 }
 
 // GenerateUser generates a user credential based on the provided parameters.
 func (ch *CredHub) GenerateUser(name string, gen generate.User, overwrite bool) (credentials.User, error) {
 	var cred credentials.User
-	err := ch.generateCredential(name, "user", gen, overwrite, &cred)
+	err := ch.generateCredential(name, "user", gen, overwriteFields(overwrite), &cred)
 	return cred, err
 }
 
 // GenerateCertificate generates a certificate credential based on the provided parameters.
 func (ch *CredHub) GenerateCertificate(name string, gen generate.Certificate, overwrite bool) (credentials.Certificate, error) {
 	var cred credentials.Certificate
-	err := ch.generateCredential(name, "certificate", gen, overwrite, &cred)
+	err := ch.generateCredential(name, "certificate", gen, overwriteFields(overwrite), &cred)
 	return cred, err
 }
 
 // GenerateRSA generates an RSA credential based on the provided parameters.
 func (ch *CredHub) GenerateRSA(name string, gen generate.RSA, overwrite bool) (credentials.RSA, error) {
 	var cred credentials.RSA
-	err := ch.generateCredential(name, "rsa", gen, overwrite, &cred)
+	err := ch.generateCredential(name, "rsa", gen, overwriteFields(overwrite), &cred)
 	return cred, err
 }
 
 // GenerateSSH generates an SSH credential based on the provided parameters.
 func (ch *CredHub) GenerateSSH(name string, gen generate.SSH, overwrite bool) (credentials.SSH, error) {
 	var cred credentials.SSH
-	err := ch.generateCredential(name, "ssh", gen, overwrite, &cred)
+	err := ch.generateCredential(name, "ssh", gen, overwriteFields(overwrite), &cred)
 	return cred, err
 }
 
 // GenerateCredential generates any credential type based on the credType given provided parameters.
 func (ch *CredHub) GenerateCredential(name, credType string, gen interface{}, overwrite bool) (credentials.Credential, error) {
 	var cred credentials.Credential
-	err := ch.generateCredential(name, credType, gen, overwrite, &cred)
+	err := ch.generateCredential(name, credType, gen, overwriteFields(overwrite), &cred)
 	return cred, err
 }
 
-func (ch *CredHub) generateCredential(name, credType string, gen interface{}, overwrite bool, cred interface{}) error {
+// GenerateCredentialWithMode generates any credential type based on the credType given provided parameters.
+// The mode controls whether an existing credential is replaced; see Mode.
+func (ch *CredHub) GenerateCredentialWithMode(name, credType string, gen interface{}, mode Mode) (credentials.Credential, error) {
+	var cred credentials.Credential
+
+	fields, err := ch.modeFields(mode)
+	if err != nil {
+		return cred, err
+	}
+
+	err = ch.generateCredential(name, credType, gen, fields, &cred)
+	return cred, err
+}
+
+func (ch *CredHub) generateCredential(name, credType string, gen interface{}, writeFields map[string]interface{}, cred interface{}) error {
 	requestBody := map[string]interface{}{}
 	requestBody["name"] = name
 	requestBody["type"] = credType
 	requestBody["parameters"] = gen
-	requestBody["overwrite"] = overwrite
+	for field, writeValue := range writeFields {
+		requestBody[field] = writeValue
+	}
 
 	if user, ok := gen.(generate.User); ok {
 		requestBody["value"] = map[string]string{"username": user.Username}
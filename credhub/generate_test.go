@@ -474,4 +474,63 @@ var _ = Describe("Generate", func() {
 
 		})
 	})
+
+	Describe("GenerateCredentialWithMode()", func() {
+		requestBody := func(dummy *DummyAuth) map[string]interface{} {
+			var body map[string]interface{}
+			raw, _ := ioutil.ReadAll(dummy.Request.Body)
+			json.Unmarshal(raw, &body)
+			return body
+		}
+
+		It("sends the mode to servers that support it", func() {
+			dummy := &DummyAuth{Response: &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       ioutil.NopCloser(bytes.NewBufferString(`{"id":"some-id","name":"/example-password","type":"password","value":"some-password"}`)),
+			}}
+			ch, _ := New("https://example.com", Auth(dummy.Builder()), ServerVersion("1.6.0"))
+
+			cred, err := ch.GenerateCredentialWithMode("/example-password", "password", generate.Password{Length: 12}, Converge)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cred.Id).To(Equal("some-id"))
+			body := requestBody(dummy)
+			Expect(body["mode"]).To(Equal("converge"))
+			Expect(body).NotTo(HaveKey("overwrite"))
+		})
+
+		It("sends the overwrite flag to older servers", func() {
+			dummy := &DummyAuth{Response: &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       ioutil.NopCloser(bytes.NewBufferString(`{}`)),
+			}}
+			ch, _ := New("https://example.com", Auth(dummy.Builder()), ServerVersion("1.5.0"))
+
+			_, err := ch.GenerateCredentialWithMode("/example-password", "password", generate.Password{}, NoOverwrite)
+
+			Expect(err).NotTo(HaveOccurred())
+			body := requestBody(dummy)
+			Expect(body["overwrite"]).To(BeFalse())
+			Expect(body).NotTo(HaveKey("mode"))
+		})
+
+		It("does not converge on older servers", func() {
+			dummy := &DummyAuth{}
+			ch, _ := New("https://example.com", Auth(dummy.Builder()), ServerVersion("1.5.0"))
+
+			_, err := ch.GenerateCredentialWithMode("/example-password", "password", generate.Password{}, Converge)
+
+			Expect(err).To(MatchError("converge mode requires CredHub server 1.6.0 or later"))
+			Expect(dummy.Request).To(BeNil())
+		})
+
+		It("rejects unknown modes", func() {
+			dummy := &DummyAuth{}
+			ch, _ := New("https://example.com", Auth(dummy.Builder()), ServerVersion("1.6.0"))
+
+			_, err := ch.GenerateCredentialWithMode("/example-password", "password", generate.Password{}, Mode("sometimes"))
+
+			Expect(err).To(MatchError("mode must be one of overwrite, no-overwrite or converge"))
+		})
+	})
 })
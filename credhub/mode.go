@@ -0,0 +1,53 @@
+package credhub
+
+import "errors"
+
+// Mode controls what a set or generate request does when the credential already exists.
+type Mode string
+
+const (
+	// Overwrite always writes a new version.
+	Overwrite Mode = "overwrite"
+	// NoOverwrite leaves an existing credential unchanged.
+	NoOverwrite Mode = "no-overwrite"
+	// Converge writes a new version only if the value or generation parameters differ from
+	// the existing credential.
+	Converge Mode = "converge"
+)
+
+// ParseMode returns the Mode named by s.
+func ParseMode(s string) (Mode, error) {
+	switch mode := Mode(s); mode {
+	case Overwrite, NoOverwrite, Converge:
+		return mode, nil
+	default:
+		return "", errors.New("mode must be one of overwrite, no-overwrite or converge")
+	}
+}
+
+// modeFields returns the request fields that select mode. Servers before 1.6.0 only
+// understand the overwrite flag, which cannot express converge.
+func (ch *CredHub) modeFields(mode Mode) (map[string]interface{}, error) {
+	if _, err := ParseMode(string(mode)); err != nil {
+		return nil, err
+	}
+
+	capabilities, err := ch.Capabilities()
+	if err != nil {
+		return nil, err
+	}
+
+	if capabilities.Mode {
+		return map[string]interface{}{"mode": string(mode)}, nil
+	}
+
+	if mode == Converge {
+		return nil, errors.New("converge mode requires CredHub server 1.6.0 or later")
+	}
+
+	return overwriteFields(mode == Overwrite), nil
+}
+
+func overwriteFields(overwrite bool) map[string]interface{} {
+	return map[string]interface{}{"overwrite": overwrite}
+}
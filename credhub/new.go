@@ -2,6 +2,7 @@ package credhub
 
 import (
 	"net/url"
+	"sync"
 
 	"github.com/cloudfoundry-incubator/credhub-cli/credhub/auth"
 	version "github.com/hashicorp/go-version"
@@ -21,9 +22,10 @@ func New(target string, options ...Option) (*CredHub, error) {
 	}
 
 	credhub := &CredHub{
-		ApiURL:      target,
-		baseURL:     baseURL,
-		authBuilder: auth.Noop,
+		ApiURL:         target,
+		baseURL:        baseURL,
+		authBuilder:    auth.Noop,
+		serverInfoLock: &sync.Mutex{},
 	}
 
 	for _, option := range options {
@@ -42,12 +44,26 @@ func New(target string, options ...Option) (*CredHub, error) {
 }
 
 func (ch *CredHub) ServerVersion() (*version.Version, error) {
+	serverVersion, _, err := ch.serverInfo()
+	if err != nil {
+		return nil, err
+	}
+	return version.NewVersion(serverVersion)
+}
+
+// serverInfo returns the server version and the features reported by /info,
+// fetching them once and caching them for concurrent requests.
+func (ch *CredHub) serverInfo() (string, map[string]bool, error) {
+	ch.serverInfoLock.Lock()
+	defer ch.serverInfoLock.Unlock()
+
 	if ch.cachedServerVersion == "" {
 		info, err := ch.Info()
 		if err != nil {
-			return nil, err
+			return "", nil, err
 		}
 		ch.cachedServerVersion = info.App.Version
+		ch.cachedFeatures = info.Features
 	}
-	return version.NewVersion(ch.cachedServerVersion)
+	return ch.cachedServerVersion, ch.cachedFeatures, nil
 }
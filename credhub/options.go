@@ -3,7 +3,11 @@ package credhub
 import (
 	"crypto/x509"
 	"errors"
+	"io"
+	"net"
+	"net/http"
 	"net/url"
+	"time"
 
 	"github.com/cloudfoundry-incubator/credhub-cli/credhub/auth"
 )
@@ -65,3 +69,56 @@ func ServerVersion(serverVersion string) Option {
 		return nil
 	}
 }
+
+// Timeout sets the time limit for requests to the CredHub and auth servers, including
+// reading the response body. A timeout of zero means no timeout. Defaults to DefaultTimeout.
+func Timeout(timeout time.Duration) Option {
+	return func(c *CredHub) error {
+		c.timeout = &timeout
+		return nil
+	}
+}
+
+// HTTPClient specifies the http.Client used for requests to the CredHub and auth servers.
+// The client is copied, not modified. CaCerts and SkipTLSValidation do not apply to it, so
+// its transport must be configured for the server's TLS certificate.
+func HTTPClient(client *http.Client) Option {
+	return func(c *CredHub) error {
+		if client == nil {
+			return errors.New("http client must not be nil")
+		}
+		c.httpClient = client
+		return nil
+	}
+}
+
+// Dialer specifies how connections to the CredHub and auth servers are opened,
+// for example through a SOCKS5 proxy or an SSH tunnel. HTTP proxy settings from
+// the environment are not used with a dialer. Dialer does not apply to the
+// client given with HTTPClient.
+func Dialer(dial func(network, addr string) (net.Conn, error)) Option {
+	return func(c *CredHub) error {
+		c.dial = dial
+		return nil
+	}
+}
+
+// Tracer writes every request to the CredHub and auth servers, and its response, to w.
+// Secrets are redacted; see NewTracingTransport. Requests are traced as they are sent,
+// after any Middleware.
+func Tracer(w io.Writer) Option {
+	return func(c *CredHub) error {
+		c.tracer = w
+		return nil
+	}
+}
+
+// Middleware wraps the transport of every request, for example to add headers,
+// metrics or logging. When given several times, the first middleware sees each
+// request first.
+func Middleware(middleware ...func(http.RoundTripper) http.RoundTripper) Option {
+	return func(c *CredHub) error {
+		c.middleware = append(c.middleware, middleware...)
+		return nil
+	}
+}
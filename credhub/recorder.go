@@ -0,0 +1,301 @@
+package credhub
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+)
+
+// RecorderMode selects whether the Recorder option records interactions with the
+// CredHub and auth servers or replays them.
+type RecorderMode int
+
+const (
+	// RecordMode sends requests to the servers and writes each request and its
+	// response to the cassette.
+	RecordMode RecorderMode = iota
+	// ReplayMode answers requests from the cassette without using the network.
+	ReplayMode
+)
+
+// Cassette holds recorded interactions with the CredHub and auth servers.
+type Cassette struct {
+	Interactions []Interaction `json:"interactions"`
+}
+
+// Interaction is a recorded request and its response.
+type Interaction struct {
+	Request  RecordedRequest  `json:"request"`
+	Response RecordedResponse `json:"response"`
+}
+
+// RecordedRequest is a request in a cassette. Requests are matched on their
+// method, path and query; the host and body are not compared.
+type RecordedRequest struct {
+	Method  string      `json:"method"`
+	URL     string      `json:"url"`
+	Headers http.Header `json:"headers,omitempty"`
+	Body    string      `json:"body,omitempty"`
+}
+
+// RecordedResponse is a response in a cassette.
+type RecordedResponse struct {
+	Status  int         `json:"status"`
+	Headers http.Header `json:"headers,omitempty"`
+	Body    string      `json:"body,omitempty"`
+}
+
+// Recorder records the interactions with the CredHub and auth servers to the
+// cassette file, or replays them from it, depending on mode.
+//
+// Recorded secrets are scrubbed: authorization headers, secret form fields,
+// and every string in credential values and tokens are replaced with
+// "[REDACTED]", so replayed credentials have the shape of the originals but
+// not their values. Replayed requests are answered by the first unused
+// interaction with the same method, path and query, and fail when there is none.
+//
+// Clients in the same process that use the same cassette in the same mode share
+// it; opening it in the other mode starts a new recording or replay.
+func Recorder(cassette string, mode RecorderMode) Option {
+	return func(c *CredHub) error {
+		recorder, err := openRecorder(cassette, mode)
+		c.recorder = recorder
+		return err
+	}
+}
+
+// NewRecordingTransport returns an http.RoundTripper that records the requests
+// sent through next to the cassette, or replays them, like the Recorder option.
+// Use it for clients not created by New, such as those talking to UAA directly.
+func NewRecordingTransport(cassette string, mode RecorderMode, next http.RoundTripper) (http.RoundTripper, error) {
+	recorder, err := openRecorder(cassette, mode)
+	if err != nil {
+		return nil, err
+	}
+
+	return recorder.transport(next), nil
+}
+
+var (
+	recordersMutex sync.Mutex
+	recorders      = map[string]*recorder{}
+)
+
+func openRecorder(cassette string, mode RecorderMode) (*recorder, error) {
+	recordersMutex.Lock()
+	defer recordersMutex.Unlock()
+
+	if recorder, ok := recorders[cassette]; ok && recorder.mode == mode {
+		return recorder, nil
+	}
+
+	recorder := &recorder{path: cassette, mode: mode}
+
+	if mode == ReplayMode {
+		contents, err := ioutil.ReadFile(cassette)
+		if err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(contents, &recorder.cassette); err != nil {
+			return nil, fmt.Errorf("cassette %s cannot be parsed: %s", cassette, err)
+		}
+		recorder.replayed = make([]bool, len(recorder.cassette.Interactions))
+	}
+
+	recorders[cassette] = recorder
+
+	return recorder, nil
+}
+
+type recorder struct {
+	mutex    sync.Mutex
+	path     string
+	mode     RecorderMode
+	cassette Cassette
+	replayed []bool
+}
+
+// transport returns a transport that records the requests sent through next,
+// or replays them without using next.
+func (r *recorder) transport(next http.RoundTripper) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+
+	if r.mode == ReplayMode {
+		return roundTripper(r.replay)
+	}
+
+	return roundTripper(func(request *http.Request) (*http.Response, error) {
+		return r.record(next, request)
+	})
+}
+
+type roundTripper func(*http.Request) (*http.Response, error)
+
+func (f roundTripper) RoundTrip(request *http.Request) (*http.Response, error) {
+	return f(request)
+}
+
+func (r *recorder) record(next http.RoundTripper, request *http.Request) (*http.Response, error) {
+	requestBody, err := readBody(&request.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := next.RoundTrip(request)
+	if err != nil {
+		return response, err
+	}
+
+	responseBody, err := readBody(&response.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	// The scrubbed body can differ in length from the one received.
+	responseHeaders := scrubHeaders(response.Header)
+	responseHeaders.Del("Content-Length")
+
+	interaction := Interaction{
+		Request: RecordedRequest{
+			Method:  request.Method,
+			URL:     request.URL.String(),
+			Headers: scrubHeaders(request.Header),
+			Body:    string(scrubBody(request.Header.Get("Content-Type"), requestBody)),
+		},
+		Response: RecordedResponse{
+			Status:  response.StatusCode,
+			Headers: responseHeaders,
+			Body:    string(scrubBody(response.Header.Get("Content-Type"), responseBody)),
+		},
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.cassette.Interactions = append(r.cassette.Interactions, interaction)
+
+	var contents bytes.Buffer
+	encoder := json.NewEncoder(&contents)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(r.cassette); err != nil {
+		return nil, err
+	}
+	if err := ioutil.WriteFile(r.path, contents.Bytes(), 0600); err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+func (r *recorder) replay(request *http.Request) (*http.Response, error) {
+	if request.Body != nil {
+		request.Body.Close()
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	for i, interaction := range r.cassette.Interactions {
+		if r.replayed[i] || !matchesRequest(interaction.Request, request) {
+			continue
+		}
+		r.replayed[i] = true
+
+		header := http.Header{}
+		for name, values := range interaction.Response.Headers {
+			header[name] = values
+		}
+
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %s", interaction.Response.Status, http.StatusText(interaction.Response.Status)),
+			StatusCode:    interaction.Response.Status,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        header,
+			Body:          ioutil.NopCloser(strings.NewReader(interaction.Response.Body)),
+			ContentLength: int64(len(interaction.Response.Body)),
+			Request:       request,
+		}, nil
+	}
+
+	return nil, fmt.Errorf("cassette %s has no unused interaction for %s %s", r.path, request.Method, request.URL.RequestURI())
+}
+
+func matchesRequest(recorded RecordedRequest, request *http.Request) bool {
+	if recorded.Method != request.Method {
+		return false
+	}
+
+	recordedURL, err := url.Parse(recorded.URL)
+	if err != nil {
+		return false
+	}
+
+	return recordedURL.Path == request.URL.Path && recordedURL.Query().Encode() == request.URL.Query().Encode()
+}
+
+func scrubHeaders(header http.Header) http.Header {
+	scrubbed := http.Header{}
+	for name, values := range header {
+		scrubbed[name] = values
+		for _, secret := range redactedHeaders {
+			if http.CanonicalHeaderKey(secret) == name {
+				scrubbed[name] = []string{redacted}
+			}
+		}
+	}
+
+	return scrubbed
+}
+
+// scrubBody redacts secrets like redactBody, but keeps the shape of JSON
+// values so that replayed credentials can still be decoded.
+func scrubBody(contentType string, body []byte) []byte {
+	if strings.HasPrefix(contentType, "application/x-www-form-urlencoded") {
+		return redactBody(contentType, body)
+	}
+
+	var document interface{}
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	if err := decoder.Decode(&document); err != nil {
+		return body
+	}
+
+	var scrubbed bytes.Buffer
+	encoder := json.NewEncoder(&scrubbed)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(scrubJSON(document, false)); err != nil {
+		return []byte(redacted)
+	}
+
+	return bytes.TrimSuffix(scrubbed.Bytes(), []byte("\n"))
+}
+
+func scrubJSON(document interface{}, secret bool) interface{} {
+	switch typed := document.(type) {
+	case map[string]interface{}:
+		for key, child := range typed {
+			typed[key] = scrubJSON(child, secret || isRedactedJSONField(key))
+		}
+	case []interface{}:
+		for i, child := range typed {
+			typed[i] = scrubJSON(child, secret)
+		}
+	case string:
+		if secret {
+			return redacted
+		}
+	}
+
+	return document
+}
@@ -0,0 +1,107 @@
+package credhub_test
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/cloudfoundry-incubator/credhub-cli/credhub"
+)
+
+var _ = Describe("Recorder", func() {
+	var (
+		dir      string
+		cassette string
+	)
+
+	BeforeEach(func() {
+		var err error
+		dir, err = ioutil.TempDir("", "credhub-cassette")
+		Expect(err).NotTo(HaveOccurred())
+		cassette = filepath.Join(dir, "cassette.json")
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(dir)
+	})
+
+	record := func() {
+		testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			switch r.URL.Path {
+			case "/info":
+				w.Write([]byte(`{"app":{"name":"CredHub","version":"1.6.0"}}`))
+			default:
+				Expect(r.URL.RawQuery).To(Equal("name=%2Fexample-certificate&versions=1"))
+				w.Write([]byte(`{"data":[{"id":"some-id","name":"/example-certificate","type":"certificate","value":{"ca":"ca-pem","certificate":"cert-pem","private_key":"secret-key"},"version_created_at":"2017-01-01T04:07:18Z"}]}`))
+			}
+		}))
+		defer testServer.Close()
+
+		ch, err := New(testServer.URL, Recorder(cassette, RecordMode), Middleware(func(next http.RoundTripper) http.RoundTripper {
+			return roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+				r.Header.Set("Authorization", "bearer secret-token")
+				return next.RoundTrip(r)
+			})
+		}))
+		Expect(err).NotTo(HaveOccurred())
+
+		certificate, err := ch.GetLatestCertificate("/example-certificate")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(certificate.Value.PrivateKey).To(Equal("secret-key"))
+	}
+
+	It("records interactions with secrets scrubbed", func() {
+		record()
+
+		contents, err := ioutil.ReadFile(cassette)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(string(contents)).To(ContainSubstring(`"method": "GET"`))
+		Expect(string(contents)).To(ContainSubstring(`/api/v1/data?name=%2Fexample-certificate&versions=1`))
+		Expect(string(contents)).To(ContainSubstring(`"status": 200`))
+		Expect(string(contents)).To(ContainSubstring(`\"id\":\"some-id\"`))
+		Expect(string(contents)).To(ContainSubstring(`\"private_key\":\"[REDACTED]\"`))
+		Expect(string(contents)).NotTo(ContainSubstring("secret-key"))
+		Expect(string(contents)).NotTo(ContainSubstring("Content-Length"))
+		Expect(string(contents)).NotTo(ContainSubstring("secret-token"))
+	})
+
+	It("replays recorded interactions without a server", func() {
+		record()
+
+		ch, err := New("https://credhub.example.com:8844", Recorder(cassette, ReplayMode))
+		Expect(err).NotTo(HaveOccurred())
+
+		certificate, err := ch.GetLatestCertificate("/example-certificate")
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(certificate.Id).To(Equal("some-id"))
+		Expect(certificate.Value.Certificate).To(Equal("[REDACTED]"))
+		Expect(certificate.Value.PrivateKey).To(Equal("[REDACTED]"))
+	})
+
+	It("fails requests that were not recorded", func() {
+		record()
+
+		ch, _ := New("https://credhub.example.com:8844", Recorder(cassette, ReplayMode))
+
+		_, err := ch.GetLatestCertificate("/example-certificate")
+		Expect(err).NotTo(HaveOccurred())
+
+		_, err = ch.GetLatestCertificate("/example-certificate")
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("has no unused interaction for GET /api/v1/data?name=%2Fexample-certificate&versions=1"))
+	})
+
+	It("returns an error when the cassette cannot be read", func() {
+		_, err := New("https://credhub.example.com:8844", Recorder(filepath.Join(dir, "missing.json"), ReplayMode))
+
+		Expect(err).To(HaveOccurred())
+	})
+})
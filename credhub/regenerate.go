@@ -5,7 +5,6 @@ import (
 	"net/http"
 
 	"github.com/cloudfoundry-incubator/credhub-cli/credhub/credentials"
-	version "github.com/hashicorp/go-version"
 )
 
 // Regenerate generates and returns a new credential version using the same parameters existing credential. The returned credential may be of any type.
@@ -17,17 +16,14 @@ func (ch *CredHub) Regenerate(name string) (credentials.Credential, error) {
 	requestBody := map[string]interface{}{}
 	requestBody["name"] = name
 
-	if ch.cachedServerVersion != "" {
-		serverVersion, err := ch.ServerVersion()
-		if err != nil {
-			return credentials.Credential{}, err
-		}
-
-		constraints, err := version.NewConstraint("< 1.4.0")
-		if constraints.Check(serverVersion) {
-			regenerateEndpoint = "/api/v1/data"
-			requestBody["regenerate"] = true
-		}
+	capabilities, err := ch.Capabilities()
+	if err != nil {
+		return credentials.Credential{}, err
+	}
+
+	if !capabilities.RegenerateEndpoint {
+		regenerateEndpoint = "/api/v1/data"
+		requestBody["regenerate"] = true
 	}
 
 	resp, err := ch.Request(http.MethodPost, regenerateEndpoint, nil, requestBody)
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"io/ioutil"
 	"net/http"
+	"net/http/httptest"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -93,4 +94,26 @@ var _ = Describe("Regenerate", func() {
 			Expect(requestBody["regenerate"]).To(Equal(true))
 		})
 	})
+
+	Context("when no server version is given", func() {
+		It("checks /info before choosing the endpoint", func() {
+			var paths []string
+			testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				paths = append(paths, r.URL.Path)
+				if r.URL.Path == "/info" {
+					w.Write([]byte(`{"app":{"name":"CredHub","version":"1.3.0"}}`))
+					return
+				}
+				w.Write([]byte(`{"name":"/example-password","type":"password","value":"new"}`))
+			}))
+			defer testServer.Close()
+
+			ch, _ := New(testServer.URL)
+
+			_, err := ch.Regenerate("/example-password")
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(paths).To(Equal([]string{"/info", "/api/v1/data"}))
+		})
+	})
 })
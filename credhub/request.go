@@ -38,6 +38,9 @@ func (ch *CredHub) request(client requester, method string, pathStr string, quer
 	if err != nil {
 		return nil, err
 	}
+	if ch.ctx != nil {
+		req = req.WithContext(ch.ctx)
+	}
 
 	req.Header.Set("Content-Type", "application/json")
 
@@ -59,7 +62,7 @@ func (ch *CredHub) checkForServerError(resp *http.Response) error {
 		defer resp.Body.Close()
 		dec := json.NewDecoder(resp.Body)
 
-		respErr := &Error{}
+		respErr := &Error{StatusCode: resp.StatusCode}
 
 		if err := dec.Decode(respErr); err != nil {
 			return err
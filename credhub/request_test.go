@@ -70,6 +70,7 @@ var _ = Describe("Request()", func() {
 			_, err = ch.Request("GET", "/example-password", nil, nil)
 
 			Expect(err).To(MatchError("error occurred"))
+			Expect(err.(*Error).StatusCode).To(Equal(400))
 		})
 	})
 })
@@ -9,4 +9,7 @@ type Info struct {
 	AuthServer struct {
 		URL string
 	} `json:"auth-server"`
+	// Features lists the API features supported by servers that report them,
+	// keyed by the capability name, e.g. "interpolate".
+	Features map[string]bool `json:"features"`
 }
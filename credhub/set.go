@@ -11,7 +11,7 @@ import (
 // SetValue sets a value credential with a user-provided value.
 func (ch *CredHub) SetValue(name string, value values.Value, overwrite bool) (credentials.Value, error) {
 	var cred credentials.Value
-	err := ch.setCredential(name, "value", value, overwrite, &cred)
+	err := ch.setCredential(name, "value", value, overwriteFields(overwrite), &cred)
 
 	return cred, err
 }
@@ -19,7 +19,7 @@ func (ch *CredHub) SetValue(name string, value values.Value, overwrite bool) (cr
 // SetJSON sets a JSON credential with a user-provided value.
 func (ch *CredHub) SetJSON(name string, value values.JSON, overwrite bool) (credentials.JSON, error) {
 	var cred credentials.JSON
-	err := ch.setCredential(name, "json", value, overwrite, &cred)
+	err := ch.setCredential(name, "json", value, overwriteFields(overwrite), &cred)
 
 	return cred, err
 }
@@ -27,7 +27,7 @@ func (ch *CredHub) SetJSON(name string, value values.JSON, overwrite bool) (cred
 // SetPassword sets a password credential with a user-provided value.
 func (ch *CredHub) SetPassword(name string, value values.Password, overwrite bool) (credentials.Password, error) {
 	var cred credentials.Password
-	err := ch.setCredential(name, "password", value, overwrite, &cred)
+	err := ch.setCredential(name, "password", value, overwriteFields(overwrite), &cred)
 
 	return cred, err
 }
@@ -35,7 +35,7 @@ func (ch *CredHub) SetPassword(name string, value values.Password, overwrite boo
 // SetUser sets a user credential with a user-provided value.
 func (ch *CredHub) SetUser(name string, value values.User, overwrite bool) (credentials.User, error) {
 	var cred credentials.User
-	err := ch.setCredential(name, "user", value, overwrite, &cred)
+	err := ch.setCredential(name, "user", value, overwriteFields(overwrite), &cred)
 
 	return cred, err
 }
@@ -43,7 +43,7 @@ func (ch *CredHub) SetUser(name string, value values.User, overwrite bool) (cred
 // SetCertificate sets a certificate credential with a user-provided value.
 func (ch *CredHub) SetCertificate(name string, value values.Certificate, overwrite bool) (credentials.Certificate, error) {
 	var cred credentials.Certificate
-	err := ch.setCredential(name, "certificate", value, overwrite, &cred)
+	err := ch.setCredential(name, "certificate", value, overwriteFields(overwrite), &cred)
 
 	return cred, err
 }
@@ -51,7 +51,7 @@ func (ch *CredHub) SetCertificate(name string, value values.Certificate, overwri
 // SetRSA sets an RSA credential with a user-provided value.
 func (ch *CredHub) SetRSA(name string, value values.RSA, overwrite bool) (credentials.RSA, error) {
 	var cred credentials.RSA
-	err := ch.setCredential(name, "rsa", value, overwrite, &cred)
+	err := ch.setCredential(name, "rsa", value, overwriteFields(overwrite), &cred)
 
 	return cred, err
 }
@@ -59,7 +59,7 @@ func (ch *CredHub) SetRSA(name string, value values.RSA, overwrite bool) (creden
 // SetSSH sets an SSH credential with a user-provided value.
 func (ch *CredHub) SetSSH(name string, value values.SSH, overwrite bool) (credentials.SSH, error) {
 	var cred credentials.SSH
-	err := ch.setCredential(name, "ssh", value, overwrite, &cred)
+	err := ch.setCredential(name, "ssh", value, overwriteFields(overwrite), &cred)
 
 	return cred, err
 }
@@ -67,17 +67,62 @@ func (ch *CredHub) SetSSH(name string, value values.SSH, overwrite bool) (creden
 // SetCredential sets a credential of any type with a user-provided value. 
 func (ch *CredHub) SetCredential(name, credType string, value interface{}, overwrite bool) (credentials.Credential, error) {
 	var cred credentials.Credential
-	err := ch.setCredential(name, credType, value, overwrite, &cred)
+	err := ch.setCredential(name, credType, value, overwriteFields(overwrite), &cred)
 
 	return cred, err
 }
 
-func (ch *CredHub) setCredential(name, credType string, value interface{}, overwrite bool, cred interface{}) error {
+// SetCredentialWithMode sets a credential of any type with a user-provided value.
+// The mode controls whether an existing credential is replaced; see Mode.
+func (ch *CredHub) SetCredentialWithMode(name, credType string, value interface{}, mode Mode) (credentials.Credential, error) {
+	var cred credentials.Credential
+
+	fields, err := ch.modeFields(mode)
+	if err != nil {
+		return cred, err
+	}
+
+	err = ch.setCredential(name, credType, value, fields, &cred)
+	return cred, err
+}
+
+// SetCredentialIfCurrent sets a credential of any type, but only if its current version has
+// the expected ID. Otherwise a *VersionConflictError is returned and nothing is written.
+//
+// The version is checked with a separate request immediately before writing, because the
+// server has no conditional write. This narrows the race with concurrent writers but does not
+// close it: a write that lands between the check and the write is silently overwritten.
+func (ch *CredHub) SetCredentialIfCurrent(name, credType string, value interface{}, expectedVersionID string) (credentials.Credential, error) {
+	if err := ch.CheckCurrentVersion(name, expectedVersionID); err != nil {
+		return credentials.Credential{}, err
+	}
+
+	return ch.SetCredential(name, credType, value, true)
+}
+
+// CheckCurrentVersion returns a *VersionConflictError if the current version of a credential
+// does not have the expected ID.
+func (ch *CredHub) CheckCurrentVersion(name, expectedVersionID string) error {
+	current, err := ch.GetLatestVersion(name)
+	if err != nil {
+		return err
+	}
+
+	if current.Id != expectedVersionID {
+		return &VersionConflictError{Name: current.Name, ExpectedId: expectedVersionID, CurrentId: current.Id}
+	}
+
+	return nil
+}
+
+func (ch *CredHub) setCredential(name, credType string, value interface{}, writeFields map[string]interface{}, cred interface{}) error {
 	requestBody := map[string]interface{}{}
 	requestBody["name"] = name
 	requestBody["type"] = credType
 	requestBody["value"] = value
-	requestBody["overwrite"] = overwrite
+	for field, writeValue := range writeFields {
+		requestBody[field] = writeValue
+	}
 	resp, err := ch.Request(http.MethodPut, "/api/v1/data", nil, requestBody)
 
 	if err != nil {
@@ -6,6 +6,7 @@ import (
 	"errors"
 	"io/ioutil"
 	"net/http"
+	"net/http/httptest"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -603,4 +604,46 @@ var _ = Describe("Set", func() {
 		})
 	})
 
+	Describe("SetCredentialIfCurrent()", func() {
+		var (
+			testServer *httptest.Server
+			puts       int
+		)
+
+		BeforeEach(func() {
+			puts = 0
+			testServer = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method == http.MethodPut {
+					puts++
+					w.Write([]byte(`{"id":"new-id","name":"/example-password","type":"password","value":"new-password","version_created_at":"2017-01-01T04:07:18Z"}`))
+					return
+				}
+				w.Write([]byte(`{"data":[{"id":"current-id","name":"/example-password","type":"password","value":"old-password","version_created_at":"2017-01-01T04:07:18Z"}]}`))
+			}))
+		})
+
+		AfterEach(func() {
+			testServer.Close()
+		})
+
+		It("sets the credential when the current version is the expected one", func() {
+			ch, _ := New(testServer.URL, ServerVersion("1.4.0"))
+
+			cred, err := ch.SetCredentialIfCurrent("/example-password", "password", "new-password", "current-id")
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cred.Id).To(Equal("new-id"))
+			Expect(puts).To(Equal(1))
+		})
+
+		It("returns a conflict error without writing when the version has moved", func() {
+			ch, _ := New(testServer.URL, ServerVersion("1.4.0"))
+
+			_, err := ch.SetCredentialIfCurrent("/example-password", "password", "new-password", "stale-id")
+
+			Expect(err).To(Equal(&VersionConflictError{Name: "/example-password", ExpectedId: "stale-id", CurrentId: "current-id"}))
+			Expect(err).To(MatchError("credential /example-password is at version current-id, not the expected version stale-id"))
+			Expect(puts).To(BeZero())
+		})
+	})
 })
@@ -0,0 +1,254 @@
+package credhub
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/cloudfoundry-incubator/credhub-cli/credhub/credentials"
+)
+
+// TLSOptions controls how TLSConfigFromCertificate keeps its certificate
+// up to date.
+type TLSOptions struct {
+	// Context stops the background refresh when done. Defaults to
+	// context.Background(), refreshing for the life of the process.
+	Context context.Context
+
+	// RefreshInterval is how often to check whether the certificate has been
+	// regenerated. Defaults to one minute.
+	RefreshInterval time.Duration
+
+	// RenewBefore is how long before the certificate expires to fetch the
+	// latest version, regardless of RefreshInterval. Defaults to one hour.
+	RenewBefore time.Duration
+
+	// OnError, if set, is called with errors from the background refresh.
+	// The last good certificate is kept in use after an error.
+	OnError func(error)
+
+	// ServerName is the host or IP address that server certificates are
+	// verified against when the connection has no server name, as when
+	// connecting to an IP address. Without it such connections fail.
+	ServerName string
+}
+
+// TLSConfigFromCertificate returns a tls.Config whose certificate and trusted
+// CA follow the named certificate credential.
+//
+// The certificate and private key are served from GetCertificate and
+// GetClientCertificate. The credential's CA is used to verify servers, and,
+// when ClientAuth is set on the returned config, to verify clients. The
+// credential is fetched again whenever it is regenerated and shortly before
+// it expires.
+//
+// Peer verification is done in VerifyConnection against the current CA, so
+// InsecureSkipVerify is set on the returned config and must be left set.
+// Server certificates must match the server name of the connection, or
+// opts.ServerName when there is none; IP addresses are matched against the IP
+// SANs of the certificate. A server is never verified against the CredHub
+// host, and connections with neither name are refused.
+func TLSConfigFromCertificate(ch *CredHub, name string, opts TLSOptions) (*tls.Config, error) {
+	if opts.Context == nil {
+		opts.Context = context.Background()
+	}
+	if opts.RefreshInterval <= 0 {
+		opts.RefreshInterval = time.Minute
+	}
+	if opts.RenewBefore <= 0 {
+		opts.RenewBefore = time.Hour
+	}
+
+	source := &tlsSource{ch: ch, name: name}
+	if err := source.fetch(); err != nil {
+		return nil, err
+	}
+
+	config := &tls.Config{
+		InsecureSkipVerify: true,
+		GetCertificate: func(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+			return source.certificate(), nil
+		},
+		GetClientCertificate: func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+			return source.certificate(), nil
+		},
+		VerifyConnection: func(state tls.ConnectionState) error {
+			// Clients do not send IP addresses as server names, so the
+			// name is empty when connecting to an IP address.
+			host := state.ServerName
+			if host == "" {
+				host = opts.ServerName
+			}
+			if host == "" {
+				return errors.New("no server name to verify the server certificate against")
+			}
+
+			if err := verifyPeer(state, x509.VerifyOptions{Roots: source.roots()}); err != nil {
+				return err
+			}
+
+			return state.PeerCertificates[0].VerifyHostname(host)
+		},
+	}
+
+	config.GetConfigForClient = func(*tls.ClientHelloInfo) (*tls.Config, error) {
+		serverConfig := config.Clone()
+		serverConfig.GetConfigForClient = nil
+		serverConfig.InsecureSkipVerify = false
+		serverConfig.VerifyConnection = nil
+		serverConfig.ClientCAs = source.roots()
+		return serverConfig, nil
+	}
+
+	go source.refresh(opts)
+
+	return config, nil
+}
+
+type tlsSource struct {
+	ch   *CredHub
+	name string
+
+	mu       sync.RWMutex
+	id       string
+	cert     *tls.Certificate
+	pool     *x509.CertPool
+	notAfter time.Time
+}
+
+func (s *tlsSource) certificate() *tls.Certificate {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.cert
+}
+
+func (s *tlsSource) roots() *x509.CertPool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.pool
+}
+
+func (s *tlsSource) current() (string, time.Time) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.id, s.notAfter
+}
+
+func (s *tlsSource) fetch() error {
+	credential, err := s.ch.GetLatestCertificate(s.name)
+	if err != nil {
+		return err
+	}
+
+	return s.update(credential)
+}
+
+func (s *tlsSource) update(credential credentials.Certificate) error {
+	cert, err := tls.X509KeyPair([]byte(credential.Value.Certificate), []byte(credential.Value.PrivateKey))
+	if err != nil {
+		return err
+	}
+
+	leaf, err := x509.ParseCertificate(cert.Certificate[0])
+	if err != nil {
+		return err
+	}
+
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM([]byte(credential.Value.Ca)) {
+		return errors.New("certificate credential " + s.name + " does not contain a valid CA")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.id = credential.Id
+	s.cert = &cert
+	s.pool = pool
+	s.notAfter = leaf.NotAfter
+
+	return nil
+}
+
+// refresh installs every new version reported by Watch, and fetches the
+// latest version once when the current one is about to expire.
+func (s *tlsSource) refresh(opts TLSOptions) {
+	changes := s.ch.Watch(opts.Context, []string{s.name}, opts.RefreshInterval)
+	renewed := ""
+
+	for s.next(opts, changes, &renewed) {
+	}
+}
+
+func (s *tlsSource) next(opts TLSOptions, changes <-chan CredentialChange, renewed *string) bool {
+	id, notAfter := s.current()
+
+	var renew <-chan time.Time
+	if *renewed != id {
+		timer := time.NewTimer(time.Until(notAfter.Add(-opts.RenewBefore)))
+		defer timer.Stop()
+		renew = timer.C
+	}
+
+	var err error
+	select {
+	case <-opts.Context.Done():
+		return false
+	case change, ok := <-changes:
+		if !ok {
+			return false
+		}
+		switch {
+		case change.Err != nil:
+			err = change.Err
+		case change.Id == id:
+			return true
+		default:
+			err = s.install(change.Credential)
+		}
+	case <-renew:
+		*renewed = id
+		err = s.fetch()
+	}
+
+	if err != nil && opts.OnError != nil {
+		opts.OnError(err)
+	}
+
+	return true
+}
+
+func (s *tlsSource) install(credential credentials.Credential) error {
+	data, err := json.Marshal(credential)
+	if err != nil {
+		return err
+	}
+
+	var certificate credentials.Certificate
+	if err := json.Unmarshal(data, &certificate); err != nil {
+		return err
+	}
+
+	return s.update(certificate)
+}
+
+func verifyPeer(state tls.ConnectionState, opts x509.VerifyOptions) error {
+	if len(state.PeerCertificates) == 0 {
+		return errors.New("no peer certificate presented")
+	}
+
+	opts.Intermediates = x509.NewCertPool()
+	for _, cert := range state.PeerCertificates[1:] {
+		opts.Intermediates.AddCert(cert)
+	}
+
+	_, err := state.PeerCertificates[0].Verify(opts)
+	return err
+}
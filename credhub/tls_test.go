@@ -0,0 +1,241 @@
+package credhub_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/cloudfoundry-incubator/credhub-cli/credhub"
+)
+
+var _ = Describe("TLSConfigFromCertificate()", func() {
+	var (
+		testServer *httptest.Server
+		mu         sync.Mutex
+		responses  map[string]string
+		ctx        context.Context
+		cancel     context.CancelFunc
+	)
+
+	setCertificate := func(name, id, ca, certificate, privateKey string) {
+		value, _ := json.Marshal(map[string]string{"ca": ca, "certificate": certificate, "private_key": privateKey})
+
+		mu.Lock()
+		defer mu.Unlock()
+		responses[name] = `{"data":[{"id":"` + id + `","name":"` + name + `","type":"certificate","value":` + string(value) + `,"version_created_at":"2017-01-01T04:07:18Z"}]}`
+	}
+
+	BeforeEach(func() {
+		testServer = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			mu.Lock()
+			defer mu.Unlock()
+
+			response, ok := responses[r.URL.Query().Get("name")]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				w.Write([]byte(`{"error":"The request could not be completed because the credential does not exist or you do not have sufficient authorization."}`))
+				return
+			}
+			w.Write([]byte(response))
+		}))
+
+		responses = map[string]string{}
+		ctx, cancel = context.WithCancel(context.Background())
+	})
+
+	AfterEach(func() {
+		cancel()
+		testServer.Close()
+	})
+
+	It("serves the certificate and picks up regenerated versions", func() {
+		ca, cert, key, leaf := generateCertificate(time.Hour)
+		setCertificate("/example-certificate", "id-1", ca, cert, key)
+
+		ch, _ := New(testServer.URL, ServerVersion("1.4.0"))
+		config, err := TLSConfigFromCertificate(ch, "/example-certificate", TLSOptions{Context: ctx, RefreshInterval: 10 * time.Millisecond})
+		Expect(err).NotTo(HaveOccurred())
+
+		served, err := config.GetCertificate(&tls.ClientHelloInfo{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(served.Certificate[0]).To(Equal(leaf.Raw))
+
+		ca, cert, key, leaf = generateCertificate(time.Hour)
+		setCertificate("/example-certificate", "id-2", ca, cert, key)
+
+		Eventually(func() []byte {
+			served, _ := config.GetClientCertificate(&tls.CertificateRequestInfo{})
+			return served.Certificate[0]
+		}).Should(Equal(leaf.Raw))
+	})
+
+	It("fetches the latest version when the certificate is about to expire", func() {
+		ca, cert, key, _ := generateCertificate(time.Hour)
+		setCertificate("/example-certificate", "id-1", ca, cert, key)
+
+		ch, _ := New(testServer.URL, ServerVersion("1.4.0"))
+		config, err := TLSConfigFromCertificate(ch, "/example-certificate", TLSOptions{Context: ctx, RefreshInterval: time.Hour, RenewBefore: 2 * time.Hour})
+		Expect(err).NotTo(HaveOccurred())
+
+		ca, cert, key, leaf := generateCertificate(time.Hour)
+		setCertificate("/example-certificate", "id-2", ca, cert, key)
+
+		Eventually(func() []byte {
+			served, _ := config.GetCertificate(&tls.ClientHelloInfo{})
+			return served.Certificate[0]
+		}).Should(Equal(leaf.Raw))
+	})
+
+	It("verifies peers against the credential's CA in both directions", func() {
+		ca, cert, key, _ := generateCertificate(time.Hour)
+		setCertificate("/example-certificate", "id-1", ca, cert, key)
+
+		ch, _ := New(testServer.URL, ServerVersion("1.4.0"))
+		config, err := TLSConfigFromCertificate(ch, "/example-certificate", TLSOptions{Context: ctx})
+		Expect(err).NotTo(HaveOccurred())
+
+		serverConfig := config.Clone()
+		serverConfig.ClientAuth = tls.RequireAndVerifyClientCert
+		clientConfig := config.Clone()
+		clientConfig.ServerName = "localhost"
+
+		Expect(handshake(serverConfig, clientConfig)).To(Succeed())
+
+		clientConfig.ServerName = "example.com"
+		Expect(handshake(serverConfig, clientConfig)).NotTo(Succeed())
+
+		otherCa, otherCert, otherKey, _ := generateCertificate(time.Hour)
+		setCertificate("/other-certificate", "id-2", otherCa, otherCert, otherKey)
+		other, err := TLSConfigFromCertificate(ch, "/other-certificate", TLSOptions{Context: ctx})
+		Expect(err).NotTo(HaveOccurred())
+		other.ServerName = "localhost"
+
+		Expect(handshake(serverConfig, other)).NotTo(Succeed())
+	})
+
+	It("refuses servers without a server name instead of verifying them against the CredHub host", func() {
+		ca, cert, key, _ := generateCertificate(time.Hour, net.ParseIP("127.0.0.1"))
+		setCertificate("/example-certificate", "id-1", ca, cert, key)
+
+		ch, _ := New(testServer.URL, ServerVersion("1.4.0"))
+		Expect(testServer.URL).To(HavePrefix("http://127.0.0.1:"))
+
+		config, err := TLSConfigFromCertificate(ch, "/example-certificate", TLSOptions{Context: ctx})
+		Expect(err).NotTo(HaveOccurred())
+
+		clientConfig := config.Clone()
+		clientConfig.ServerName = "127.0.0.1"
+
+		err = handshake(config, clientConfig)
+		Expect(err).To(MatchError(ContainSubstring("no server name to verify the server certificate against")))
+	})
+
+	It("verifies servers without a server name against the given ServerName", func() {
+		ca, cert, key, _ := generateCertificate(time.Hour, net.ParseIP("10.0.0.1"))
+		setCertificate("/example-certificate", "id-1", ca, cert, key)
+
+		ch, _ := New(testServer.URL, ServerVersion("1.4.0"))
+		config, err := TLSConfigFromCertificate(ch, "/example-certificate", TLSOptions{Context: ctx, ServerName: "10.0.0.2"})
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(handshake(config, config.Clone())).NotTo(Succeed())
+
+		config, err = TLSConfigFromCertificate(ch, "/example-certificate", TLSOptions{Context: ctx, ServerName: "10.0.0.1"})
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(handshake(config, config.Clone())).To(Succeed())
+	})
+
+	It("returns an error when the credential cannot be fetched", func() {
+		ch, _ := New(testServer.URL, ServerVersion("1.4.0"))
+
+		_, err := TLSConfigFromCertificate(ch, "/example-certificate", TLSOptions{Context: ctx})
+
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+func handshake(serverConfig, clientConfig *tls.Config) error {
+	serverConn, clientConn := net.Pipe()
+	defer serverConn.Close()
+	defer clientConn.Close()
+
+	serverErr := make(chan error, 1)
+	go func() {
+		server := tls.Server(serverConn, serverConfig)
+		serverErr <- server.Handshake()
+		server.Close()
+	}()
+
+	if err := tls.Client(clientConn, clientConfig).Handshake(); err != nil {
+		return err
+	}
+
+	// With TLS 1.3 the server verifies the client certificate after the
+	// client has finished its handshake, so unblock any alert it sends.
+	clientConn.Close()
+
+	return <-serverErr
+}
+
+// generateCertificate returns a CA and a leaf certificate for localhost and
+// ipAddresses signed by it, in PEM format, along with the parsed leaf.
+func generateCertificate(validity time.Duration, ipAddresses ...net.IP) (string, string, string, *x509.Certificate) {
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	Expect(err).NotTo(HaveOccurred())
+
+	caTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "example-ca"},
+		NotBefore:             time.Now().Add(-time.Minute),
+		NotAfter:              time.Now().Add(validity),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	caDER, err := x509.CreateCertificate(rand.Reader, caTemplate, caTemplate, &caKey.PublicKey, caKey)
+	Expect(err).NotTo(HaveOccurred())
+	caCert, err := x509.ParseCertificate(caDER)
+	Expect(err).NotTo(HaveOccurred())
+
+	leafKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	Expect(err).NotTo(HaveOccurred())
+
+	leafTemplate := &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: "localhost"},
+		DNSNames:     []string{"localhost"},
+		IPAddresses:  ipAddresses,
+		NotBefore:    time.Now().Add(-time.Minute),
+		NotAfter:     time.Now().Add(validity),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+	leafDER, err := x509.CreateCertificate(rand.Reader, leafTemplate, caCert, &leafKey.PublicKey, caKey)
+	Expect(err).NotTo(HaveOccurred())
+	leaf, err := x509.ParseCertificate(leafDER)
+	Expect(err).NotTo(HaveOccurred())
+
+	keyDER, err := x509.MarshalECPrivateKey(leafKey)
+	Expect(err).NotTo(HaveOccurred())
+
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: caDER})),
+		string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: leafDER})),
+		string(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})),
+		leaf
+}
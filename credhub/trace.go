@@ -0,0 +1,187 @@
+package credhub
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const redacted = "[REDACTED]"
+
+// Headers, form fields and JSON fields whose values are not written to traces.
+var (
+	redactedHeaders    = []string{"Authorization", "Cookie", "Set-Cookie"}
+	redactedFormFields = []string{"client_secret", "password", "refresh_token"}
+	redactedJSONFields = []string{"value", "password", "client_secret", "access_token", "refresh_token"}
+)
+
+// NewTracingTransport returns an http.RoundTripper that writes every request and
+// response sent through next to w, with their timing and bodies. Authorization
+// headers, secrets in form fields and credential values in JSON bodies are
+// redacted.
+//
+// Use the Tracer option to trace the requests of a CredHub client; use
+// NewTracingTransport for other clients, such as those talking to UAA directly.
+func NewTracingTransport(w io.Writer, next http.RoundTripper) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+
+	return &tracingTransport{writer: w, next: next}
+}
+
+type tracingTransport struct {
+	mutex  sync.Mutex
+	writer io.Writer
+	next   http.RoundTripper
+}
+
+func (t *tracingTransport) RoundTrip(request *http.Request) (*http.Response, error) {
+	var trace bytes.Buffer
+
+	requestBody, err := readBody(&request.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	fmt.Fprintf(&trace, "REQUEST: [%s] %s %s\n", time.Now().Format(time.RFC3339), request.Method, request.URL)
+	writeHeaders(&trace, request.Header)
+	writeBody(&trace, request.Header.Get("Content-Type"), requestBody)
+
+	start := time.Now()
+	response, err := t.next.RoundTrip(request)
+	elapsed := time.Since(start)
+
+	if err != nil {
+		fmt.Fprintf(&trace, "RESPONSE ERROR: %s (%s)\n\n", err, elapsed)
+		t.write(trace.Bytes())
+		return response, err
+	}
+
+	responseBody, err := readBody(&response.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	fmt.Fprintf(&trace, "RESPONSE: %s (%s)\n", response.Status, elapsed)
+	writeHeaders(&trace, response.Header)
+	writeBody(&trace, response.Header.Get("Content-Type"), responseBody)
+
+	t.write(trace.Bytes())
+
+	return response, nil
+}
+
+// write writes a whole request and response at once, so that traces of
+// concurrent requests are not interleaved.
+func (t *tracingTransport) write(trace []byte) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	t.writer.Write(trace)
+}
+
+// readBody reads and replaces body so that it can still be sent or read.
+func readBody(body *io.ReadCloser) ([]byte, error) {
+	if *body == nil {
+		return nil, nil
+	}
+
+	contents, err := ioutil.ReadAll(*body)
+	(*body).Close()
+	*body = ioutil.NopCloser(bytes.NewReader(contents))
+
+	return contents, err
+}
+
+func writeHeaders(trace *bytes.Buffer, header http.Header) {
+	names := make([]string, 0, len(header))
+	for name := range header {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		value := strings.Join(header[name], ", ")
+		for _, secret := range redactedHeaders {
+			if http.CanonicalHeaderKey(secret) == name {
+				value = redacted
+			}
+		}
+		fmt.Fprintf(trace, "%s: %s\n", name, value)
+	}
+	trace.WriteString("\n")
+}
+
+func writeBody(trace *bytes.Buffer, contentType string, body []byte) {
+	if len(body) == 0 {
+		return
+	}
+
+	trace.Write(redactBody(contentType, body))
+	trace.WriteString("\n\n")
+}
+
+func redactBody(contentType string, body []byte) []byte {
+	if strings.HasPrefix(contentType, "application/x-www-form-urlencoded") {
+		form, err := url.ParseQuery(string(body))
+		if err != nil {
+			return []byte(redacted)
+		}
+		for _, field := range redactedFormFields {
+			if _, ok := form[field]; ok {
+				form.Set(field, redacted)
+			}
+		}
+		return []byte(strings.Replace(form.Encode(), url.QueryEscape(redacted), redacted, -1))
+	}
+
+	var document interface{}
+	if err := json.Unmarshal(body, &document); err != nil {
+		return body
+	}
+
+	redactedBody, err := json.Marshal(redactJSON(document))
+	if err != nil {
+		return []byte(redacted)
+	}
+
+	return redactedBody
+}
+
+func redactJSON(document interface{}) interface{} {
+	switch typed := document.(type) {
+	case map[string]interface{}:
+		for key, child := range typed {
+			if isRedactedJSONField(key) {
+				typed[key] = redacted
+			} else {
+				typed[key] = redactJSON(child)
+			}
+		}
+	case []interface{}:
+		for i, child := range typed {
+			typed[i] = redactJSON(child)
+		}
+	}
+
+	return document
+}
+
+func isRedactedJSONField(key string) bool {
+	for _, field := range redactedJSONFields {
+		if key == field {
+			return true
+		}
+	}
+
+	return false
+}
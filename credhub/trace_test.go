@@ -0,0 +1,92 @@
+package credhub_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/cloudfoundry-incubator/credhub-cli/credhub"
+)
+
+var _ = Describe("Tracing", func() {
+	var testServer *httptest.Server
+
+	BeforeEach(func() {
+		testServer = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			if r.URL.Path == "/oauth/token" {
+				w.Write([]byte(`{"access_token":"secret-access-token","token_type":"bearer"}`))
+				return
+			}
+			body, _ := ioutil.ReadAll(r.Body)
+			Expect(string(body)).To(MatchJSON(`{"name":"/example-password","type":"password","value":"hunter2","overwrite":true}`))
+			w.Write([]byte(`{"id":"some-id","name":"/example-password","type":"password","value":"hunter2","version_created_at":"2017-01-01T04:07:18Z"}`))
+		}))
+	})
+
+	AfterEach(func() {
+		testServer.Close()
+	})
+
+	It("traces requests and responses with secrets redacted", func() {
+		trace := &bytes.Buffer{}
+		addAuthorization := func(next http.RoundTripper) http.RoundTripper {
+			return roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+				r.Header.Set("Authorization", "bearer secret-access-token")
+				return next.RoundTrip(r)
+			})
+		}
+
+		ch, _ := New(testServer.URL, ServerVersion("1.6.0"), Tracer(trace), Middleware(addAuthorization))
+
+		password, err := ch.SetPassword("/example-password", "hunter2", true)
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(password.Value)).To(Equal("hunter2"))
+
+		traced := trace.String()
+		Expect(traced).To(MatchRegexp(`REQUEST: \[.+\] PUT ` + testServer.URL + `/api/v1/data\n`))
+		Expect(traced).To(ContainSubstring("Authorization: [REDACTED]\n"))
+		Expect(traced).To(ContainSubstring(`"value":"[REDACTED]"`))
+		Expect(traced).To(ContainSubstring(`"name":"/example-password"`))
+		Expect(traced).To(MatchRegexp(`RESPONSE: 200 OK \(.+\)\n`))
+		Expect(traced).To(ContainSubstring(`"id":"some-id"`))
+		Expect(traced).NotTo(ContainSubstring("hunter2"))
+		Expect(traced).NotTo(ContainSubstring("secret-access-token"))
+	})
+
+	It("redacts secrets in form bodies", func() {
+		trace := &bytes.Buffer{}
+		client := &http.Client{Transport: NewTracingTransport(trace, nil)}
+
+		response, err := client.Post(testServer.URL+"/oauth/token", "application/x-www-form-urlencoded",
+			strings.NewReader("client_id=credhub_cli&client_secret=cli-secret&grant_type=password&password=hunter2&username=admin"))
+
+		Expect(err).NotTo(HaveOccurred())
+		body, _ := ioutil.ReadAll(response.Body)
+		Expect(string(body)).To(ContainSubstring("secret-access-token"))
+
+		traced := trace.String()
+		Expect(traced).To(ContainSubstring("client_id=credhub_cli&client_secret=[REDACTED]&grant_type=password&password=[REDACTED]&username=admin\n"))
+		Expect(traced).To(ContainSubstring(`"access_token":"[REDACTED]"`))
+		Expect(traced).NotTo(ContainSubstring("cli-secret"))
+		Expect(traced).NotTo(ContainSubstring("hunter2"))
+		Expect(traced).NotTo(ContainSubstring("secret-access-token"))
+	})
+
+	It("traces failed requests", func() {
+		trace := &bytes.Buffer{}
+		ch, _ := New("http://localhost:1", ServerVersion("1.6.0"), Tracer(trace))
+
+		_, err := ch.GetLatestVersion("/example-password")
+
+		Expect(err).To(HaveOccurred())
+		Expect(trace.String()).To(ContainSubstring("REQUEST: "))
+		Expect(trace.String()).To(ContainSubstring("RESPONSE ERROR: "))
+	})
+})
@@ -0,0 +1,124 @@
+package credhub
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/cloudfoundry-incubator/credhub-cli/credhub/credentials"
+)
+
+// maxWatchBackoffFactor caps how far Watch backs off after errors, as a
+// multiple of its poll interval.
+const maxWatchBackoffFactor = 16
+
+// CredentialChange describes a new version of a watched credential.
+type CredentialChange struct {
+	// Name of the credential that changed
+	Name string
+
+	// PreviousId is the version id seen before the change. It is empty for the
+	// first version observed for Name.
+	PreviousId string
+
+	// Id is the version id of Credential
+	Id string
+
+	// Credential is the new version, as returned by GetLatestVersion
+	Credential credentials.Credential
+
+	// Err is the error returned when Name could not be fetched. When it is
+	// set, the other fields apart from Name are empty.
+	Err error
+}
+
+// Watch polls the named credentials every interval and sends a CredentialChange
+// whenever the latest version of one of them differs from the version seen
+// before. The current version of each credential is sent as soon as it is
+// first fetched, with an empty PreviousId.
+//
+// When a credential cannot be fetched a CredentialChange with the error in Err
+// is sent, and the poll interval is doubled, up to 16 times interval, until
+// every credential is fetched successfully again. Polling waits for each
+// change to be received.
+//
+// Requests in flight are cancelled and the returned channel is closed after
+// ctx is done. When interval is not positive, a single CredentialChange with
+// an error is sent and the channel is closed.
+func (ch *CredHub) Watch(ctx context.Context, names []string, interval time.Duration) <-chan CredentialChange {
+	changes := make(chan CredentialChange)
+	watched := ch.withContext(ctx)
+
+	go func() {
+		defer close(changes)
+
+		if interval <= 0 {
+			select {
+			case changes <- CredentialChange{Err: errors.New("watch interval must be positive")}:
+			case <-ctx.Done():
+			}
+			return
+		}
+
+		seen := map[string]credentials.Metadata{}
+		wait := interval
+
+		for {
+			failed := false
+
+			for _, name := range names {
+				credential, err := watched.GetLatestVersion(name)
+				if ctx.Err() != nil {
+					return
+				}
+				if err != nil {
+					failed = true
+					select {
+					case changes <- CredentialChange{Name: name, Err: err}:
+					case <-ctx.Done():
+						return
+					}
+					continue
+				}
+
+				previous, ok := seen[name]
+				if ok && previous.Id == credential.Id && previous.VersionCreatedAt == credential.VersionCreatedAt {
+					continue
+				}
+
+				change := CredentialChange{
+					Name:       name,
+					PreviousId: previous.Id,
+					Id:         credential.Id,
+					Credential: credential,
+				}
+
+				select {
+				case changes <- change:
+					seen[name] = credential.Metadata
+				case <-ctx.Done():
+					return
+				}
+			}
+
+			if failed {
+				wait *= 2
+				if max := interval * maxWatchBackoffFactor; wait > max {
+					wait = max
+				}
+			} else {
+				wait = interval
+			}
+
+			timer := time.NewTimer(wait)
+			select {
+			case <-timer.C:
+			case <-ctx.Done():
+				timer.Stop()
+				return
+			}
+		}
+	}()
+
+	return changes
+}
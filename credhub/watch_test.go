@@ -0,0 +1,163 @@
+package credhub_test
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/cloudfoundry-incubator/credhub-cli/credhub"
+)
+
+var _ = Describe("Watch()", func() {
+	var (
+		testServer *httptest.Server
+		mu         sync.Mutex
+		versions   map[string]string
+		requests   int
+		ctx        context.Context
+		cancel     context.CancelFunc
+	)
+
+	BeforeEach(func() {
+		versions = map[string]string{"/example-password": "id-1"}
+		requests = 0
+
+		testServer = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Query().Get("name") == "/slow" {
+				ioutil.ReadAll(r.Body)
+				select {
+				case <-r.Context().Done():
+				case <-time.After(5 * time.Second):
+				}
+				return
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			requests++
+			name := r.URL.Query().Get("name")
+			id, ok := versions[name]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				w.Write([]byte(`{"error":"The request could not be completed because the credential does not exist or you do not have sufficient authorization."}`))
+				return
+			}
+
+			w.Write([]byte(`{"data":[{"id":"` + id + `","name":"` + name + `","type":"password","value":"value-` + id + `","version_created_at":"2017-01-01T04:07:18Z"}]}`))
+		}))
+
+		ctx, cancel = context.WithCancel(context.Background())
+	})
+
+	AfterEach(func() {
+		cancel()
+		testServer.Close()
+	})
+
+	It("sends the current version and then every new version", func() {
+		ch, _ := New(testServer.URL, ServerVersion("1.4.0"))
+
+		changes := ch.Watch(ctx, []string{"/example-password"}, 10*time.Millisecond)
+
+		var change CredentialChange
+		Eventually(changes).Should(Receive(&change))
+		Expect(change.Name).To(Equal("/example-password"))
+		Expect(change.PreviousId).To(BeEmpty())
+		Expect(change.Id).To(Equal("id-1"))
+		Expect(change.Credential.Value).To(Equal("value-id-1"))
+
+		Consistently(changes, "50ms").ShouldNot(Receive())
+
+		mu.Lock()
+		versions["/example-password"] = "id-2"
+		mu.Unlock()
+
+		Eventually(changes).Should(Receive(&change))
+		Expect(change.PreviousId).To(Equal("id-1"))
+		Expect(change.Id).To(Equal("id-2"))
+		Expect(change.Credential.Value).To(Equal("value-id-2"))
+	})
+
+	It("reports errors and keeps watching until the credential exists", func() {
+		ch, _ := New(testServer.URL, ServerVersion("1.4.0"))
+
+		changes := ch.Watch(ctx, []string{"/missing"}, 10*time.Millisecond)
+
+		var change CredentialChange
+		Eventually(changes).Should(Receive(&change))
+		Expect(change.Name).To(Equal("/missing"))
+		Expect(change.Id).To(BeEmpty())
+		Expect(change.Err).To(MatchError(ContainSubstring("the credential does not exist")))
+
+		mu.Lock()
+		versions["/missing"] = "id-1"
+		mu.Unlock()
+
+		Eventually(func() string {
+			Eventually(changes).Should(Receive(&change))
+			return change.Id
+		}).Should(Equal("id-1"))
+		Expect(change.Name).To(Equal("/missing"))
+		Expect(change.Err).NotTo(HaveOccurred())
+	})
+
+	It("backs off while requests fail", func() {
+		ch, _ := New(testServer.URL, ServerVersion("1.4.0"))
+
+		changes := ch.Watch(ctx, []string{"/missing"}, 10*time.Millisecond)
+		go func() {
+			for range changes {
+			}
+		}()
+
+		time.Sleep(200 * time.Millisecond)
+
+		mu.Lock()
+		defer mu.Unlock()
+		Expect(requests).To(BeNumerically("<", 10))
+	})
+
+	It("reports a non-positive interval instead of polling", func() {
+		ch, _ := New(testServer.URL, ServerVersion("1.4.0"))
+
+		changes := ch.Watch(ctx, []string{"/example-password"}, 0)
+
+		var change CredentialChange
+		Eventually(changes).Should(Receive(&change))
+		Expect(change.Err).To(MatchError("watch interval must be positive"))
+		Eventually(changes).Should(BeClosed())
+
+		mu.Lock()
+		defer mu.Unlock()
+		Expect(requests).To(Equal(0))
+	})
+
+	It("closes the channel when the context is done", func() {
+		ch, _ := New(testServer.URL, ServerVersion("1.4.0"))
+
+		changes := ch.Watch(ctx, []string{"/example-password"}, 10*time.Millisecond)
+		Eventually(changes).Should(Receive())
+
+		cancel()
+
+		Eventually(changes).Should(BeClosed())
+	})
+
+	It("cancels requests in flight when the context is done", func() {
+		ch, _ := New(testServer.URL, ServerVersion("1.4.0"))
+
+		changes := ch.Watch(ctx, []string{"/slow"}, 10*time.Millisecond)
+		Consistently(changes, "50ms").ShouldNot(Receive())
+
+		cancel()
+
+		Eventually(changes, "1s").Should(BeClosed())
+	})
+})
@@ -1,83 +1,242 @@
 package errors
 
 import (
-	"errors"
 	"fmt"
 )
 
 func NewNetworkError(e error) error {
-	return errors.New(fmt.Sprintf("Error connecting to the targeted API: %#v. Please validate your target and retry your request.", e.Error()))
+	return newError(Network, fmt.Sprintf("Error connecting to the targeted API: %#v. Please validate your target and retry your request.", e.Error()))
 }
 
 func NewResponseError() error {
-	return errors.New("An error occurred when processing the response. Please validate your input and retry your request.")
+	return newError(ServerError, "An error occurred when processing the response. Please validate your input and retry your request.")
 }
 
 func NewCatchAllError() error {
-	return errors.New("The targeted API was unable to perform the request. Please validate and retry your request.")
+	return newError(ServerError, "The targeted API was unable to perform the request. Please validate and retry your request.")
 }
 
 func NewRevokedTokenError() error {
-	return errors.New("You are not currently authenticated. Please log in to continue.")
+	return newError(AuthRequired, "You are not currently authenticated. Please log in to continue.")
 }
 
 func NewFileLoadError() error {
-	return errors.New("A referenced file could not be opened. Please validate the provided filenames and permissions, then retry your request.")
+	return newError(Validation, "A referenced file could not be opened. Please validate the provided filenames and permissions, then retry your request.")
 }
 
 func NewMissingGetParametersError() error {
-	return errors.New("A name or ID must be provided. Please update and retry your request.")
+	return newError(Validation, "A name or ID must be provided. Please update and retry your request.")
 }
 
 
 func NewAuthorizationError() error {
-	return errors.New("The provided username and password combination are incorrect. Please validate your input and retry your request.")
+	return newError(AuthRequired, "The provided username and password combination are incorrect. Please validate your input and retry your request.")
 }
 
 func NewMixedAuthorizationParametersError() error {
-	return errors.New("Client and password credentials may not be combined. Please update and retry your request with a single login method.")
+	return newError(Validation, "Client and password credentials may not be combined. Please update and retry your request with a single login method.")
 }
 
 func NewPasswordAuthorizationParametersError() error {
-	return errors.New("The combination of parameters in the request is not allowed. Please validate your input and retry your request.")
+	return newError(Validation, "The combination of parameters in the request is not allowed. Please validate your input and retry your request.")
 }
 
 func NewClientAuthorizationParametersError() error {
-	return errors.New("Both client name and client secret must be provided to authenticate. Please update and retry your request.")
+	return newError(Validation, "Both client name and client secret must be provided to authenticate. Please update and retry your request.")
 }
 
 func NewRefreshError() error {
-	return errors.New("You are not currently authenticated. Please log in to continue.")
+	return newError(AuthRequired, "You are not currently authenticated. Please log in to continue.")
 }
 
 func NewForbiddenError() error {
-	return errors.New("You are not authorized to perform this action. You must log in with an elevated user or contact your administrator to continue.")
+	return newError(Forbidden, "You are not authorized to perform this action. You must log in with an elevated user or contact your administrator to continue.")
 }
 
 func NewNoMatchingCredentialsFoundError() error {
-	return errors.New("No credentials exist which match the provided parameters.")
+	return newError(NotFound, "No credentials exist which match the provided parameters.")
 }
 
 func NewAccessTokenExpiredError() error {
-	return errors.New("JWT access token expired")
+	return newError(AuthRequired, "JWT access token expired")
 }
 
 func NewSetEmptyTypeError() error {
-	return errors.New("A type must be specified when setting a credential. Valid types include 'value', 'json', 'password', 'user', 'certificate', 'ssh' and 'rsa'.")
+	return newError(Validation, "A type must be specified when setting a credential. Valid types include 'value', 'json', 'password', 'user', 'certificate', 'ssh' and 'rsa'.")
 }
 
 func NewGenerateEmptyTypeError() error {
-	return errors.New("A type must be specified when generating a credential. Valid types include 'password', 'user', 'certificate', 'ssh' and 'rsa'.")
+	return newError(Validation, "A type must be specified when generating a credential. Valid types include 'password', 'user', 'certificate', 'ssh' and 'rsa'.")
 }
 
 func NewNoApiUrlSetError() error {
-	return errors.New("An API target is not set. Please target the location of your server with `credhub api --server api.example.com` to continue.")
+	return newError(AuthRequired, "An API target is not set. Please target the location of your server with `credhub api --server api.example.com` to continue.")
 }
 
 func NewInvalidImportYamlError() error {
-	return errors.New("The referenced file does not contain valid yaml structure. Please update and retry your request.")
+	return newError(Validation, "The referenced file does not contain valid yaml structure. Please update and retry your request.")
 }
 
 func NewNoCredentialsTag() error {
-	return errors.New("The referenced import file does not begin with the key 'credentials'. The import file must contain a list of credentials under the key 'credentials'. Please update and retry your request.")
+	return newError(Validation, "The referenced import file does not begin with the key 'credentials'. The import file must contain a list of credentials under the key 'credentials'. Please update and retry your request.")
+}
+
+func NewProfileNotFoundError(name string) error {
+	return newError(NotFound, fmt.Sprintf("The profile '%s' does not exist. Please add it with `credhub target add %s --server api.example.com` to continue.", name, name))
+}
+
+func NewProfileAlreadyExistsError(name string) error {
+	return newError(Validation, fmt.Sprintf("The profile '%s' already exists. Please choose a different name or remove the existing profile to continue.", name))
+}
+
+func NewCorruptConfigError(path string, e error) error {
+	return newError(CorruptConfig, fmt.Sprintf("The config file at %s could not be parsed: %s. Please fix or remove the file and retry your request.", path, e.Error()))
+}
+
+func NewCredentialHelperError(program, action, message string) error {
+	return newError(HelperFailure, fmt.Sprintf("The credential helper '%s' failed to %s tokens: %s", program, action, message))
+}
+
+func NewUnknownHelperActionError(action, supported string) error {
+	return newError(Validation, fmt.Sprintf("The action '%s' is not supported. Supported actions are %s.", action, supported))
+}
+
+func NewDockerCredentialsNotFoundError() error {
+	return newError(NotFound, "No registry credentials exist for the provided server URL.")
+}
+
+func NewMissingServerURLError() error {
+	return newError(Validation, "A server URL must be provided on stdin. Please update and retry your request.")
+}
+
+func NewMissingGitCredentialAttributeError(attribute string) error {
+	return newError(Validation, fmt.Sprintf("The '%s' attribute must be provided on stdin. Please update and retry your request.", attribute))
+}
+
+func NewInvalidGitCredentialAttributeError(line string) error {
+	return newError(Validation, fmt.Sprintf("The line '%s' is not a valid credential attribute. Attributes must be of the form key=value.", line))
+}
+
+func NewInvalidAgentConfigError() error {
+	return newError(Validation, "The referenced agent config file does not contain valid yaml structure. Please update and retry your request.")
+}
+
+func NewMissingAgentTemplatesError() error {
+	return newError(Validation, "The referenced agent config file does not contain any templates. Please update and retry your request.")
+}
+
+func NewMissingAgentTemplateDestinationError(index int) error {
+	return newError(Validation, fmt.Sprintf("The template at index %d does not have a destination. Please update and retry your request.", index))
+}
+
+func NewInvalidAgentTemplateSourceError(destination string) error {
+	return newError(Validation, fmt.Sprintf("Exactly one of source or contents must be provided for the template rendering to %s. Please update and retry your request.", destination))
+}
+
+func NewMissingDiffParametersError() error {
+	return newError(Validation, "A name, two version IDs, or a path with either a file or a profile to compare against must be provided. Please update and retry your request.")
+}
+
+func NewRollbackTargetConflictError() error {
+	return newError(Validation, "Only one of --to-id and --steps may be provided. Please update and retry your request.")
+}
+
+func NewInvalidRollbackStepsError() error {
+	return newError(Validation, "The number of steps to roll back must be at least 1. Please update and retry your request.")
+}
+
+func NewInsufficientVersionsError(name string, steps, versions int) error {
+	return newError(Validation, fmt.Sprintf("The credential '%s' cannot be rolled back %d step(s) because it only has %d version(s). Please update and retry your request.", name, steps, versions))
+}
+
+func NewRollbackVersionMismatchError(id, name string) error {
+	return newError(Validation, fmt.Sprintf("The version '%s' is not a version of the credential '%s'. Please update and retry your request.", id, name))
+}
+
+func NewGetFailuresError(failed, total int) error {
+	return newError(PartialFailure, fmt.Sprintf("%d of %d credentials could not be retrieved.", failed, total))
+}
+
+func NewImportFailuresError(failed, total int) error {
+	return newError(PartialFailure, fmt.Sprintf("%d of %d credentials could not be imported.", failed, total))
+}
+
+func NewNoCredentialsFoundError(path string) error {
+	return newError(NotFound, fmt.Sprintf("No credentials were found under the path '%s'.", path))
+}
+
+func NewMissingDeleteParametersError() error {
+	return newError(Validation, "A name, path, name-like or regex must be provided. Please update and retry your request.")
+}
+
+func NewInvalidDeleteRegexError(err error) error {
+	return newError(Validation, fmt.Sprintf("The regex could not be parsed: %s. Please update and retry your request.", err))
+}
+
+func NewCopySourceNotFoundError(from string) error {
+	return newError(NotFound, fmt.Sprintf("No credential or path named '%s' was found. Please update and retry your request.", from))
+}
+
+func NewCopyDestinationExistsError(name string) error {
+	return newError(Validation, fmt.Sprintf("The credential '%s' already exists. Use --force to add the copied versions to it.", name))
+}
+
+func NewSyncSameProfileError() error {
+	return newError(Validation, "The source and destination profiles must be different. Please update and retry your request.")
+}
+
+func NewCopySameSourceAndDestinationError() error {
+	return newError(Validation, "The source and destination must be different. Please update and retry your request.")
+}
+
+func NewInvalidEditedValueError(credType, reason string) error {
+	return newError(Validation, fmt.Sprintf("The edited value is not a valid %s credential: %s. Please update and retry your request.", credType, reason))
+}
+
+func NewCredentialChangedError(name string) error {
+	return newError(VersionConflict, fmt.Sprintf("The credential '%s' was changed on the server while it was being edited. No changes were made.", name))
+}
+
+func NewInvalidJSONPatchError(reason string) error {
+	return newError(Validation, fmt.Sprintf("The JSON patch is not a valid list of operations: %s. Please update and retry your request.", reason))
+}
+
+func NewJSONPatchOperationError(index int, op, path, reason string) error {
+	return newError(Validation, fmt.Sprintf("The JSON patch operation %d ('%s' at '%s') could not be applied: %s. Please update and retry your request.", index, op, path, reason))
+}
+
+func NewInvalidMergePatchError(reason string) error {
+	return newError(Validation, fmt.Sprintf("The merge patch is not valid JSON: %s. Please update and retry your request.", reason))
+}
+
+func NewMissingPatchParametersError() error {
+	return newError(Validation, "Exactly one of --merge or --json-patch must be provided. Please update and retry your request.")
+}
+
+func NewPatchTypeError(name, credType string) error {
+	return newError(Validation, fmt.Sprintf("The credential '%s' has type '%s'. Only json credentials can be patched.", name, credType))
+}
+
+func NewPatchResultTypeError() error {
+	return newError(Validation, "The patched value must be a JSON object. Please update and retry your request.")
+}
+
+func NewVersionConflictError(name, expectedId, currentId string) error {
+	return newError(VersionConflict, fmt.Sprintf("The credential '%s' was not set because its current version is '%s', not '%s'. Get the current version and retry your request.", name, currentId, expectedId))
+}
+
+func NewInvalidModeError(mode string) error {
+	return newError(Validation, fmt.Sprintf("The mode '%s' is not supported. Valid modes are 'overwrite', 'no-overwrite' and 'converge'. Please update and retry your request.", mode))
+}
+
+func NewModeConflictError() error {
+	return newError(Validation, "The --mode and --no-overwrite flags cannot be combined. Please update and retry your request.")
+}
+
+func NewInvalidProxyError(proxy, reason string) error {
+	return newError(Validation, fmt.Sprintf("The proxy '%s' set by CREDHUB_PROXY is not valid: %s. Please update and retry your request.", proxy, reason))
+}
+
+func NewProxyConnectionError(proxy, reason string) error {
+	return newError(Network, fmt.Sprintf("A connection could not be opened through the proxy '%s': %s.", proxy, reason))
 }
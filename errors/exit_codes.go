@@ -0,0 +1,46 @@
+package errors
+
+// Kind classifies a failure by the exit code of the CLI and the name given to it
+// in JSON error output, so that scripts can branch on the failure type without
+// parsing messages.
+type Kind struct {
+	ExitCode int
+	Name     string
+}
+
+var (
+	General         = Kind{ExitCode: 1, Name: "error"}
+	Validation      = Kind{ExitCode: 2, Name: "validation"}
+	AuthRequired    = Kind{ExitCode: 3, Name: "auth_required"}
+	Forbidden       = Kind{ExitCode: 4, Name: "forbidden"}
+	NotFound        = Kind{ExitCode: 5, Name: "not_found"}
+	VersionConflict = Kind{ExitCode: 6, Name: "version_conflict"}
+	Network         = Kind{ExitCode: 7, Name: "network"}
+	ServerError     = Kind{ExitCode: 8, Name: "server_error"}
+	PartialFailure  = Kind{ExitCode: 9, Name: "partial_failure"}
+	CorruptConfig   = Kind{ExitCode: 10, Name: "corrupt_config"}
+	HelperFailure   = Kind{ExitCode: 11, Name: "credential_helper"}
+)
+
+type kindError struct {
+	message string
+	kind    Kind
+}
+
+func (e *kindError) Error() string {
+	return e.message
+}
+
+func newError(kind Kind, message string) error {
+	return &kindError{message: message, kind: kind}
+}
+
+// KindOf returns the kind of an error created by this package, or General for
+// any other error.
+func KindOf(err error) Kind {
+	if e, ok := err.(*kindError); ok {
+		return e.kind
+	}
+
+	return General
+}
@@ -1,9 +1,5 @@
 package errors
 
-import (
-	"errors"
-)
-
 func NewUnauthorizedError() error {
-	return errors.New("Unauthorized")
+	return newError(AuthRequired, "Unauthorized")
 }
@@ -1,30 +1,53 @@
 package main
 
 import (
-	"fmt"
 	"os"
+	"path/filepath"
 	"runtime/debug"
+	"strings"
 
 	"github.com/cloudfoundry-incubator/credhub-cli/commands"
 	"github.com/jessevdk/go-flags"
 )
 
+// credentialHelpers maps the executable names other tools look up for their
+// credential helpers to the command that implements each protocol.
+var credentialHelpers = map[string]string{
+	commands.DockerCredentialHelperName: "docker-credential",
+	commands.GitCredentialHelperName:    "git-credential",
+}
+
 func main() {
 	debug.SetTraceback("all")
 	parser := flags.NewParser(&commands.CredHub, flags.HelpFlag)
 	parser.SubcommandsOptional = true
+	credentialHelper := false
 	parser.CommandHandler = func(command flags.Commander, args []string) error {
 		if command == nil {
 			parser.WriteHelp(os.Stderr)
 			os.Exit(1)
 		}
 
+		switch command.(type) {
+		case *commands.DockerCredentialCommand, *commands.GitCredentialCommand:
+			credentialHelper = true
+		}
+
 		return command.Execute(args)
 	}
 
-	_, err := parser.Parse()
+	args := os.Args[1:]
+	if command, ok := credentialHelpers[strings.TrimSuffix(filepath.Base(os.Args[0]), ".exe")]; ok {
+		args = append([]string{command}, args...)
+	}
+
+	_, err := parser.ParseArgs(args)
 	if err != nil {
-		fmt.Fprintln(os.Stderr, err.Error())
-		os.Exit(1)
+		exitCode := commands.PrintError(os.Stderr, err, commands.CredHub.OutputJson)
+		// Credential helper protocols expect every failure to exit with 1.
+		if credentialHelper {
+			exitCode = 1
+		}
+		os.Exit(exitCode)
 	}
 }
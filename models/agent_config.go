@@ -0,0 +1,80 @@
+package models
+
+import (
+	"io/ioutil"
+	"os"
+	"time"
+
+	"github.com/cloudfoundry-incubator/credhub-cli/errors"
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	DefaultAgentInterval             = time.Minute
+	DefaultAgentTokenRefreshInterval = 10 * time.Minute
+	DefaultAgentTemplatePerms        = os.FileMode(0600)
+)
+
+// AgentConfig describes the templates rendered by `credhub agent` and how
+// often the credentials they reference are polled.
+type AgentConfig struct {
+	Interval             time.Duration   `yaml:"interval"`
+	TokenRefreshInterval time.Duration   `yaml:"token_refresh_interval"`
+	Templates            []AgentTemplate `yaml:"templates"`
+}
+
+// AgentTemplate is a Go template, read from Source or given inline as
+// Contents, that is rendered to Destination. Command is run whenever the
+// rendered file changes.
+type AgentTemplate struct {
+	Source      string      `yaml:"source"`
+	Contents    string      `yaml:"contents"`
+	Destination string      `yaml:"destination"`
+	Perms       os.FileMode `yaml:"perms"`
+	Command     string      `yaml:"command"`
+}
+
+func (agentConfig *AgentConfig) ReadFile(filepath string) error {
+	data, err := ioutil.ReadFile(filepath)
+	if err != nil {
+		return err
+	}
+
+	return agentConfig.ReadBytes(data)
+}
+
+func (agentConfig *AgentConfig) ReadBytes(data []byte) error {
+	if err := yaml.Unmarshal(data, agentConfig); err != nil {
+		return errors.NewInvalidAgentConfigError()
+	}
+
+	if len(agentConfig.Templates) == 0 {
+		return errors.NewMissingAgentTemplatesError()
+	}
+
+	if agentConfig.Interval <= 0 {
+		agentConfig.Interval = DefaultAgentInterval
+	}
+
+	if agentConfig.TokenRefreshInterval <= 0 {
+		agentConfig.TokenRefreshInterval = DefaultAgentTokenRefreshInterval
+	}
+
+	for i := range agentConfig.Templates {
+		template := &agentConfig.Templates[i]
+
+		if template.Destination == "" {
+			return errors.NewMissingAgentTemplateDestinationError(i)
+		}
+
+		if (template.Source == "") == (template.Contents == "") {
+			return errors.NewInvalidAgentTemplateSourceError(template.Destination)
+		}
+
+		if template.Perms == 0 {
+			template.Perms = DefaultAgentTemplatePerms
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,85 @@
+package models_test
+
+import (
+	"os"
+	"time"
+
+	"github.com/cloudfoundry-incubator/credhub-cli/models"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("AgentConfig", func() {
+	Describe("ReadBytes()", func() {
+		It("parses templates and durations", func() {
+			var agentConfig models.AgentConfig
+			err := agentConfig.ReadBytes([]byte(`
+interval: 30s
+templates:
+- source: /etc/app/db.yml.tmpl
+  destination: /etc/app/db.yml
+  perms: 0640
+  command: systemctl reload app
+`))
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(agentConfig.Interval).To(Equal(30 * time.Second))
+			Expect(agentConfig.Templates).To(Equal([]models.AgentTemplate{{
+				Source:      "/etc/app/db.yml.tmpl",
+				Destination: "/etc/app/db.yml",
+				Perms:       os.FileMode(0640),
+				Command:     "systemctl reload app",
+			}}))
+		})
+
+		It("applies defaults", func() {
+			var agentConfig models.AgentConfig
+			err := agentConfig.ReadBytes([]byte(`
+templates:
+- contents: "{{ (credential \"/password\").Value }}"
+  destination: /etc/app/password
+`))
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(agentConfig.Interval).To(Equal(models.DefaultAgentInterval))
+			Expect(agentConfig.TokenRefreshInterval).To(Equal(models.DefaultAgentTokenRefreshInterval))
+			Expect(agentConfig.Templates[0].Perms).To(Equal(os.FileMode(0600)))
+		})
+
+		It("requires at least one template", func() {
+			var agentConfig models.AgentConfig
+			err := agentConfig.ReadBytes([]byte(`interval: 30s`))
+
+			Expect(err).To(MatchError("The referenced agent config file does not contain any templates. Please update and retry your request."))
+		})
+
+		It("requires a destination for every template", func() {
+			var agentConfig models.AgentConfig
+			err := agentConfig.ReadBytes([]byte(`
+templates:
+- contents: foo
+`))
+
+			Expect(err).To(MatchError("The template at index 0 does not have a destination. Please update and retry your request."))
+		})
+
+		It("requires exactly one of source or contents", func() {
+			var agentConfig models.AgentConfig
+			err := agentConfig.ReadBytes([]byte(`
+templates:
+- source: /tmp/foo.tmpl
+  contents: foo
+  destination: /tmp/foo
+`))
+
+			Expect(err).To(MatchError("Exactly one of source or contents must be provided for the template rendering to /tmp/foo. Please update and retry your request."))
+		})
+
+		It("errors on invalid yaml", func() {
+			var agentConfig models.AgentConfig
+			err := agentConfig.ReadBytes([]byte(`templates: [`))
+
+			Expect(err).To(MatchError("The referenced agent config file does not contain valid yaml structure. Please update and retry your request."))
+		})
+	})
+})
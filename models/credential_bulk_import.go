@@ -102,3 +102,9 @@ func hasCredentialTag(data []byte) bool {
 
 	return hasCredentialTag
 }
+
+// UnpackYAMLValue converts a value decoded from YAML into one that can be
+// encoded as JSON, replacing maps with non-string keys.
+func UnpackYAMLValue(value interface{}) interface{} {
+	return unpackAnyType(value)
+}
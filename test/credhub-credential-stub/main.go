@@ -0,0 +1,61 @@
+// credhub-credential-stub is a credential helper used by the test suites.
+//
+// Tokens are kept in the JSON file named by CREDHUB_CREDENTIAL_STUB_FILE,
+// keyed by profile. Setting CREDHUB_CREDENTIAL_STUB_FAIL makes every action
+// fail with that value as the error message.
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/cloudfoundry-incubator/credhub-cli/config"
+)
+
+func main() {
+	if message := os.Getenv("CREDHUB_CREDENTIAL_STUB_FAIL"); message != "" {
+		fail(message)
+	}
+
+	if len(os.Args) != 2 {
+		fail("usage: credhub-credential-stub get|store|erase")
+	}
+
+	var input config.HelperTokens
+	if err := json.NewDecoder(os.Stdin).Decode(&input); err != nil {
+		fail(err.Error())
+	}
+
+	storePath := os.Getenv("CREDHUB_CREDENTIAL_STUB_FILE")
+	store := map[string]config.HelperTokens{}
+
+	if data, err := ioutil.ReadFile(storePath); err == nil {
+		json.Unmarshal(data, &store)
+	}
+
+	switch os.Args[1] {
+	case "get":
+		if tokens, ok := store[input.Profile]; ok {
+			json.NewEncoder(os.Stdout).Encode(tokens)
+		}
+		return
+	case "store":
+		store[input.Profile] = input
+	case "erase":
+		delete(store, input.Profile)
+	default:
+		fail("unknown action " + os.Args[1])
+	}
+
+	data, _ := json.Marshal(store)
+	if err := ioutil.WriteFile(storePath, data, 0600); err != nil {
+		fail(err.Error())
+	}
+}
+
+func fail(message string) {
+	fmt.Fprintln(os.Stderr, message)
+	os.Exit(1)
+}
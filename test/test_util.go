@@ -8,6 +8,14 @@ import (
 func CleanEnv() {
 	os.Unsetenv("CREDHUB_SECRET")
 	os.Unsetenv("CREDHUB_CLIENT")
+	os.Unsetenv("CREDHUB_PROFILE")
+	os.Unsetenv("CREDHUB_CONFIG_DIR")
+	os.Unsetenv("XDG_CONFIG_HOME")
+	os.Unsetenv("CREDHUB_TIMEOUT")
+	os.Unsetenv("CREDHUB_PROXY")
+	os.Unsetenv("CREDHUB_TRACE")
+	os.Unsetenv("CREDHUB_RECORD")
+	os.Unsetenv("CREDHUB_REPLAY")
 }
 
 func CreateTempDir(prefix string) string {
@@ -0,0 +1,34 @@
+package util_test
+
+import (
+	"github.com/cloudfoundry-incubator/credhub-cli/util"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("UnifiedDiff", func() {
+	It("returns nothing for equal input", func() {
+		Expect(util.UnifiedDiff("a\nb\n", "a\nb\n")).To(BeEmpty())
+	})
+
+	It("shows changed lines with context", func() {
+		from := "1\n2\n3\n4\n5\n6\n7\n8\n"
+		to := "1\n2\n3\n4\nfive\n6\n7\n8\n"
+
+		Expect(util.UnifiedDiff(from, to)).To(Equal("@@ -2,7 +2,7 @@\n 2\n 3\n 4\n-5\n+five\n 6\n 7\n 8\n"))
+	})
+
+	It("splits changes that are far apart into separate hunks", func() {
+		from := "a\n1\n2\n3\n4\n5\n6\n7\nb\n"
+		to := "A\n1\n2\n3\n4\n5\n6\n7\nB\n"
+
+		Expect(util.UnifiedDiff(from, to)).To(Equal(
+			"@@ -1,4 +1,4 @@\n-a\n+A\n 1\n 2\n 3\n" +
+				"@@ -6,4 +6,4 @@\n 5\n 6\n 7\n-b\n+B\n"))
+	})
+
+	It("handles additions to empty input", func() {
+		Expect(util.UnifiedDiff("", "a\nb\n")).To(Equal("@@ -0,0 +1,2 @@\n+a\n+b\n"))
+	})
+})
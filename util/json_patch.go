@@ -0,0 +1,275 @@
+package util
+
+import (
+	"encoding/json"
+	"reflect"
+	"strconv"
+	"strings"
+
+	credhub_errors "github.com/cloudfoundry-incubator/credhub-cli/errors"
+)
+
+type jsonPatchOperation struct {
+	Op    string      `json:"op"`
+	Path  string      `json:"path"`
+	From  string      `json:"from"`
+	Value interface{} `json:"value"`
+}
+
+// MergePatch applies an RFC 7386 merge patch to target. Keys set to null in
+// the patch are removed, objects are merged recursively and any other value
+// replaces the one in target.
+func MergePatch(target, patch interface{}) interface{} {
+	patchObject, ok := patch.(map[string]interface{})
+	if !ok {
+		return patch
+	}
+
+	targetObject, ok := target.(map[string]interface{})
+	merged := map[string]interface{}{}
+	if ok {
+		for key, value := range targetObject {
+			merged[key] = value
+		}
+	}
+
+	for key, value := range patchObject {
+		if value == nil {
+			delete(merged, key)
+		} else {
+			merged[key] = MergePatch(merged[key], value)
+		}
+	}
+
+	return merged
+}
+
+// ApplyJSONPatch applies the RFC 6902 operations in patch to document. The
+// document is not modified; either every operation applies or an error is
+// returned.
+func ApplyJSONPatch(document interface{}, patch []byte) (interface{}, error) {
+	var operations []jsonPatchOperation
+	if err := json.Unmarshal(patch, &operations); err != nil {
+		return nil, credhub_errors.NewInvalidJSONPatchError(err.Error())
+	}
+
+	result := deepCopyJSON(document)
+	for i, operation := range operations {
+		var err error
+		if result, err = applyJSONPatchOperation(result, operation); err != nil {
+			return nil, credhub_errors.NewJSONPatchOperationError(i, operation.Op, operation.Path, err.Error())
+		}
+	}
+
+	return result, nil
+}
+
+func applyJSONPatchOperation(document interface{}, operation jsonPatchOperation) (interface{}, error) {
+	switch operation.Op {
+	case "add":
+		return jsonPointerSet(document, operation.Path, deepCopyJSON(operation.Value), true)
+	case "remove":
+		document, _, err := jsonPointerRemove(document, operation.Path)
+		return document, err
+	case "replace":
+		if _, err := jsonPointerGet(document, operation.Path); err != nil {
+			return nil, err
+		}
+		return jsonPointerSet(document, operation.Path, deepCopyJSON(operation.Value), false)
+	case "move":
+		if operation.Path == operation.From {
+			return document, nil
+		}
+		if strings.HasPrefix(operation.Path, operation.From+"/") {
+			return nil, jsonPatchError("a value cannot be moved into itself")
+		}
+		document, value, err := jsonPointerRemove(document, operation.From)
+		if err != nil {
+			return nil, err
+		}
+		return jsonPointerSet(document, operation.Path, value, true)
+	case "copy":
+		value, err := jsonPointerGet(document, operation.From)
+		if err != nil {
+			return nil, err
+		}
+		return jsonPointerSet(document, operation.Path, deepCopyJSON(value), true)
+	case "test":
+		value, err := jsonPointerGet(document, operation.Path)
+		if err != nil {
+			return nil, err
+		}
+		if !reflect.DeepEqual(value, operation.Value) {
+			return nil, jsonPatchError("the value does not match")
+		}
+		return document, nil
+	default:
+		return nil, jsonPatchError("the operation is not supported")
+	}
+}
+
+type jsonPatchError string
+
+func (e jsonPatchError) Error() string {
+	return string(e)
+}
+
+func parseJSONPointer(pointer string) ([]string, error) {
+	if pointer == "" {
+		return nil, nil
+	}
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, jsonPatchError("the path must be empty or start with '/'")
+	}
+
+	tokens := strings.Split(pointer[1:], "/")
+	for i, token := range tokens {
+		tokens[i] = strings.Replace(strings.Replace(token, "~1", "/", -1), "~0", "~", -1)
+	}
+
+	return tokens, nil
+}
+
+func jsonPointerGet(document interface{}, pointer string) (interface{}, error) {
+	tokens, err := parseJSONPointer(pointer)
+	if err != nil {
+		return nil, err
+	}
+
+	value := document
+	for _, token := range tokens {
+		switch typed := value.(type) {
+		case map[string]interface{}:
+			child, ok := typed[token]
+			if !ok {
+				return nil, jsonPatchError("the path does not exist")
+			}
+			value = child
+		case []interface{}:
+			index, err := arrayIndex(token, len(typed), false)
+			if err != nil {
+				return nil, err
+			}
+			value = typed[index]
+		default:
+			return nil, jsonPatchError("the path does not exist")
+		}
+	}
+
+	return value, nil
+}
+
+// jsonPointerSet sets the value at pointer, returning the updated document.
+// Values are inserted into arrays when insert is set and replaced otherwise.
+func jsonPointerSet(document interface{}, pointer string, value interface{}, insert bool) (interface{}, error) {
+	tokens, err := parseJSONPointer(pointer)
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 0 {
+		return value, nil
+	}
+
+	parent, err := jsonPointerGet(document, pointerTo(tokens[:len(tokens)-1]))
+	if err != nil {
+		return nil, err
+	}
+	last := tokens[len(tokens)-1]
+
+	switch typed := parent.(type) {
+	case map[string]interface{}:
+		typed[last] = value
+		return document, nil
+	case []interface{}:
+		index, err := arrayIndex(last, len(typed), insert)
+		if err != nil {
+			return nil, err
+		}
+		if !insert {
+			typed[index] = value
+			return document, nil
+		}
+		updated := append(typed[:index:index], append([]interface{}{value}, typed[index:]...)...)
+		return jsonPointerSet(document, pointerTo(tokens[:len(tokens)-1]), updated, false)
+	default:
+		return nil, jsonPatchError("the parent of the path is not an object or array")
+	}
+}
+
+func jsonPointerRemove(document interface{}, pointer string) (interface{}, interface{}, error) {
+	tokens, err := parseJSONPointer(pointer)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(tokens) == 0 {
+		return nil, nil, jsonPatchError("the whole document cannot be removed")
+	}
+
+	value, err := jsonPointerGet(document, pointer)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	parentPointer := pointerTo(tokens[:len(tokens)-1])
+	parent, _ := jsonPointerGet(document, parentPointer)
+	last := tokens[len(tokens)-1]
+
+	switch typed := parent.(type) {
+	case map[string]interface{}:
+		delete(typed, last)
+		return document, value, nil
+	default:
+		array := typed.([]interface{})
+		index, _ := arrayIndex(last, len(array), false)
+		updated := append(array[:index:index], array[index+1:]...)
+		document, err := jsonPointerSet(document, parentPointer, updated, false)
+		return document, value, err
+	}
+}
+
+func pointerTo(tokens []string) string {
+	var pointer string
+	for _, token := range tokens {
+		pointer += "/" + strings.Replace(strings.Replace(token, "~", "~0", -1), "/", "~1", -1)
+	}
+
+	return pointer
+}
+
+// arrayIndex parses an array index. "-" refers to the end of the array, which
+// is only valid when inserting.
+func arrayIndex(token string, length int, insert bool) (int, error) {
+	if token == "-" && insert {
+		return length, nil
+	}
+
+	index, err := strconv.Atoi(token)
+	if err != nil || index < 0 || (token != "0" && strings.HasPrefix(token, "0")) {
+		return 0, jsonPatchError("the array index is not valid")
+	}
+
+	if index > length || (index == length && !insert) {
+		return 0, jsonPatchError("the array index is out of bounds")
+	}
+
+	return index, nil
+}
+
+func deepCopyJSON(value interface{}) interface{} {
+	switch typed := value.(type) {
+	case map[string]interface{}:
+		copied := map[string]interface{}{}
+		for key, child := range typed {
+			copied[key] = deepCopyJSON(child)
+		}
+		return copied
+	case []interface{}:
+		copied := make([]interface{}, len(typed))
+		for i, child := range typed {
+			copied[i] = deepCopyJSON(child)
+		}
+		return copied
+	default:
+		return value
+	}
+}
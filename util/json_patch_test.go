@@ -0,0 +1,87 @@
+package util_test
+
+import (
+	"encoding/json"
+
+	"github.com/cloudfoundry-incubator/credhub-cli/util"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func parseJSON(document string) interface{} {
+	var value interface{}
+	Expect(json.Unmarshal([]byte(document), &value)).To(Succeed())
+	return value
+}
+
+var _ = Describe("MergePatch", func() {
+	It("merges objects recursively and removes null keys", func() {
+		target := parseJSON(`{"a":"b","c":{"d":"e","f":"g"},"h":[1,2]}`)
+		patch := parseJSON(`{"a":"z","c":{"f":null},"h":[3],"i":true}`)
+
+		Expect(util.MergePatch(target, patch)).To(Equal(parseJSON(`{"a":"z","c":{"d":"e"},"h":[3],"i":true}`)))
+		Expect(target).To(Equal(parseJSON(`{"a":"b","c":{"d":"e","f":"g"},"h":[1,2]}`)))
+	})
+})
+
+var _ = Describe("ApplyJSONPatch", func() {
+	apply := func(document, patch string) (interface{}, error) {
+		return util.ApplyJSONPatch(parseJSON(document), []byte(patch))
+	}
+
+	It("adds, removes and replaces values", func() {
+		result, err := apply(`{"a":{"b":1},"list":["x","z"]}`, `[
+			{"op":"add","path":"/a/c","value":2},
+			{"op":"add","path":"/list/1","value":"y"},
+			{"op":"add","path":"/list/-","value":"end"},
+			{"op":"remove","path":"/a/b"},
+			{"op":"replace","path":"/list/0","value":"w"}
+		]`)
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result).To(Equal(parseJSON(`{"a":{"c":2},"list":["w","y","z","end"]}`)))
+	})
+
+	It("moves and copies values", func() {
+		result, err := apply(`{"a":{"b":1},"c":["x"]}`, `[
+			{"op":"move","from":"/a/b","path":"/moved"},
+			{"op":"copy","from":"/c","path":"/a/copied"}
+		]`)
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result).To(Equal(parseJSON(`{"a":{"copied":["x"]},"c":["x"],"moved":1}`)))
+	})
+
+	It("unescapes pointer tokens", func() {
+		result, err := apply(`{"a/b":1,"c~d":2}`, `[{"op":"replace","path":"/a~1b","value":3},{"op":"remove","path":"/c~0d"}]`)
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result).To(Equal(parseJSON(`{"a/b":3}`)))
+	})
+
+	It("fails the whole patch when a test does not match", func() {
+		document := parseJSON(`{"a":1}`)
+
+		_, err := util.ApplyJSONPatch(document, []byte(`[{"op":"replace","path":"/a","value":2},{"op":"test","path":"/a","value":1}]`))
+
+		Expect(err).To(MatchError("The JSON patch operation 1 ('test' at '/a') could not be applied: the value does not match. Please update and retry your request."))
+		Expect(document).To(Equal(parseJSON(`{"a":1}`)))
+	})
+
+	It("rejects paths that do not exist", func() {
+		_, err := apply(`{"a":1}`, `[{"op":"replace","path":"/b","value":2}]`)
+		Expect(err).To(MatchError(ContainSubstring("the path does not exist")))
+
+		_, err = apply(`{"a":[1]}`, `[{"op":"remove","path":"/a/1"}]`)
+		Expect(err).To(MatchError(ContainSubstring("the array index is out of bounds")))
+	})
+
+	It("rejects patches that are not a list of operations", func() {
+		_, err := apply(`{}`, `{"op":"add"}`)
+		Expect(err).To(MatchError(ContainSubstring("The JSON patch is not a valid list of operations")))
+
+		_, err = apply(`{}`, `[{"op":"frobnicate","path":"/a"}]`)
+		Expect(err).To(MatchError(ContainSubstring("the operation is not supported")))
+	})
+})